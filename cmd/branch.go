@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var branchCmd = &cobra.Command{
+	Use:   "branch [rev]",
+	Short: "Create a release/<version> branch at the current calendar version; tags on it are patch versions",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		rev := ""
+		if len(args) > 0 {
+			rev = args[0]
+		}
+
+		res, err := repo().CreateReleaseBranch(latestCalVer(), rev, dryRun)
+		CheckIfError(err)
+		if short {
+			fmt.Println(res.Branch)
+			return
+		}
+		if res.DryRun {
+			fmt.Printf("Would create branch '%s' (hash %s)\n", res.Branch, res.ShortHash())
+			return
+		}
+		fmt.Printf("Created branch '%s' (hash %s)\n", res.Branch, res.ShortHash())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(branchCmd)
+	branchCmd.Flags().BoolVarP(&short, "short", "s", false, "Output the branch name only")
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/socialviolation/git-calver/ver"
+	"github.com/spf13/cobra"
+)
+
+var (
+	bumpCommit  bool
+	bumpTag     bool
+	bumpMessage string
+)
+
+var bumpFilesCmd = &cobra.Command{
+	Use:   "bump-files [version]",
+	Short: "Write the next version into the [calver-file] files, eg. package.json, Chart.yaml or VERSION",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		r := repo()
+		// Only tagging is subject to the branch policy's allowed option.
+		cv := previewCalVer()
+		if bumpTag {
+			cv = nextCalVerArgs()
+		}
+
+		tag := ""
+		if len(args) > 0 {
+			tag = args[0]
+			if c, _ := ver.SplitComponent(tag); c == "" && cv.Component != "" {
+				tag = cv.Component + "/" + tag
+			}
+		} else {
+			var err error
+			tag, _, err = r.NextVersion(cv)
+			CheckIfError(err)
+		}
+		// Files and the commit message get the version without the component prefix of the tag.
+		_, version := ver.SplitComponent(tag)
+
+		files, err := r.VersionFiles()
+		CheckIfError(err)
+		if len(files) == 0 {
+			CheckIfError(fmt.Errorf("no version files configured, add a [calver-file \"<path>\"] section to the git config"))
+		}
+
+		changed, err := r.BumpFiles(files, version, dryRun)
+		verb := "Updated"
+		if dryRun {
+			verb = "Would update"
+		}
+		for _, path := range changed {
+			fmt.Printf("%s %s to %s\n", verb, path, version)
+		}
+		CheckIfError(err)
+		if len(changed) == 0 {
+			fmt.Printf("Version files already at %s\n", version)
+		}
+
+		if (bumpCommit || bumpTag) && len(changed) > 0 {
+			msg := strings.ReplaceAll(bumpMessage, "{version}", version)
+			if dryRun {
+				fmt.Printf("Would commit '%s'\n", msg)
+			} else {
+				h, err := r.CommitFiles(changed, msg)
+				CheckIfError(err)
+				fmt.Printf("Committed '%s' (hash %s)\n", msg, h.String()[:7])
+			}
+		}
+
+		if bumpTag {
+			res, err := r.Tag(ver.TagArgs{
+				CV:     cv,
+				Tag:    tag,
+				Push:   push,
+				DryRun: dryRun,
+				Force:  force,
+			})
+			if errors.Is(err, ver.ErrVersionNotNewer) {
+				err = fmt.Errorf("%w, use --force to tag anyway", err)
+			}
+			CheckIfError(err)
+			printTagged(res, "Created", "Would create")
+			printPush(res, "Pushed tag '%s' to", "Would push tag '%s' to")
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(bumpFilesCmd)
+	bumpFilesCmd.Flags().BoolVar(&bumpCommit, "commit", false, "Commit the updated files")
+	bumpFilesCmd.Flags().BoolVar(&bumpTag, "tag", false, "Commit the updated files and tag the commit with the version")
+	bumpFilesCmd.Flags().StringVarP(&bumpMessage, "message", "m", "chore(release): {version}", "Commit message, {version} is replaced by the version")
+	bumpFilesCmd.Flags().BoolVarP(&push, "push", "p", false, "Push tag after create")
+	bumpFilesCmd.Flags().BoolVar(&force, "force", false, "Create the tag even if it does not order above the latest tag")
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/socialviolation/git-calver/ver"
+	"github.com/spf13/cobra"
+)
+
+var (
+	changelogFile       string
+	changelogPrepend    bool
+	changelogUnreleased bool
+	changelogLimit      int
+)
+
+var changelogCmd = &cobra.Command{
+	Use:   "changelog",
+	Short: "Write a Keep a Changelog style CHANGELOG.md from calver tags",
+	Run: func(cmd *cobra.Command, args []string) {
+		f := latestCalVer()
+		r := repo()
+		r.FirstParent = firstParent
+
+		lim := changelogLimit
+		if lim <= 0 {
+			lim = math.MaxInt
+		}
+		groups, err := r.List(r.ReleaseRegex(f), lim, true)
+		CheckIfError(err)
+
+		var unreleased []*object.Commit
+		if changelogUnreleased {
+			from := ""
+			if len(groups) > 0 {
+				from = groups[0].Tags[0]
+			}
+			unreleased, err = r.Changelog(from, "HEAD")
+			CheckIfError(err)
+		}
+
+		existing := ""
+		if changelogPrepend && changelogFile != "-" {
+			b, err := os.ReadFile(changelogFile)
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
+				CheckIfError(err)
+			}
+			existing = string(b)
+		}
+
+		doc := ver.UpdateChangelog(existing, groups, unreleased)
+		if dryRun && changelogFile != "-" {
+			fmt.Printf("Would write %s:\n\n%s", changelogFile, doc)
+			return
+		}
+		if changelogFile == "-" {
+			fmt.Print(doc)
+			return
+		}
+
+		err = os.WriteFile(changelogFile, []byte(doc), 0644)
+		CheckIfError(err)
+		fmt.Printf("Wrote %s\n", changelogFile)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(changelogCmd)
+	changelogCmd.Flags().StringVarP(&changelogFile, "file", "o", "CHANGELOG.md", "File to write, or - for stdout")
+	changelogCmd.Flags().BoolVar(&changelogPrepend, "prepend", false, "Only add releases missing from the existing file, keeping its content")
+	changelogCmd.Flags().BoolVar(&changelogUnreleased, "unreleased", false, "Include an Unreleased section with commits since the latest tag")
+	changelogCmd.Flags().IntVarP(&changelogLimit, "limit", "l", 0, "Limit number of releases (0 for all)")
+	changelogCmd.Flags().BoolVar(&firstParent, "first-parent", false, "Follow only the first parent of merges in the changelog")
+}
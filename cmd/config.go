@@ -10,7 +10,7 @@ var formatGetCommand = &cobra.Command{
 	Use:   "format",
 	Short: "Get format from .gitconfig",
 	Run: func(cmd *cobra.Command, args []string) {
-		f, a, err := ver.GetRepoFormat()
+		f, a, err := repo().ComponentFormat(component)
 		CheckIfError(err)
 
 		if a {
@@ -28,7 +28,17 @@ var formatSetCommand = &cobra.Command{
 		f, err := ver.NewFormat(format)
 		CheckIfError(err)
 
-		err = ver.SetRepoFormat(f)
+		section := "[calver]"
+		if component != "" {
+			section = fmt.Sprintf("[calver \"%s\"]", component)
+		}
+
+		r := repo()
+		if dryRun {
+			fmt.Printf("Would set %s format to %s\n", section, f.String())
+			return
+		}
+		err = r.SetComponentFormat(component, f)
 		CheckIfError(err)
 
 		fmt.Println("format set")
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/socialviolation/git-calver/ver"
+	"github.com/spf13/cobra"
+)
+
+var containsCmd = &cobra.Command{
+	Use:   "contains <rev>",
+	Short: "Find the release that first contained a commit, and the later releases that include it",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		r := repo()
+		co, err := r.ResolveCommit(args[0])
+		CheckIfError(err)
+
+		groups, err := r.Contains(r.ReleaseRegex(latestCalVer()), args[0])
+		CheckIfError(err)
+		if machineOutput() {
+			infos := make([]ver.ReleaseInfo, 0, len(groups))
+			for _, g := range groups {
+				infos = append(infos, g.Info())
+			}
+			printInfos(infos)
+			return
+		}
+		if len(groups) == 0 {
+			CheckIfError(fmt.Errorf("commit %s is not in any release", co.Hash.String()[:7]))
+		}
+
+		if short {
+			fmt.Println(groups[0].Tag())
+			return
+		}
+		fmt.Printf("First released in %s (hash %s)\n", groups[0].Tag(), groups[0].Hash)
+		if len(groups) > 1 {
+			later := make([]string, 0, len(groups)-1)
+			for _, g := range groups[1:] {
+				later = append(later, g.Tag())
+			}
+			fmt.Printf("Also in %s\n", strings.Join(later, ", "))
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(containsCmd)
+	containsCmd.Flags().StringVar(&outputFormat, "output", "", "Machine-readable output format (json, yaml or ndjson)")
+	containsCmd.Flags().BoolVarP(&short, "short", "s", false, "Output the first release only")
+}
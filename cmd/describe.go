@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var describeDirty bool
+
+var describeCmd = &cobra.Command{
+	Use:   "describe [rev]",
+	Short: "Describe a commit relative to the nearest calver tag, eg. 2024.03.15-7-gabc1234",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		rev := ""
+		if len(args) > 0 {
+			rev = args[0]
+		}
+
+		r := repo()
+		r.FirstParent = firstParent
+		// Uncommitted changes only say something about HEAD.
+		d, err := r.Describe(r.ReleaseRegex(latestCalVer()), rev, describeDirty && (rev == "" || rev == "HEAD"))
+		CheckIfError(err)
+		fmt.Println(d.String())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(describeCmd)
+	describeCmd.Flags().BoolVar(&describeDirty, "dirty", true, "Append -dirty when describing HEAD with uncommitted changes")
+	describeCmd.Flags().BoolVar(&firstParent, "first-parent", false, "Follow only the first parent of merges")
+}
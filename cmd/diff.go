@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var diffMarkdown bool
+
+var diffCmd = &cobra.Command{
+	Use:   "diff [<from> [<to>]]",
+	Short: "List the commits, authors and changed files between two releases (default: the two latest tags)",
+	Args:  cobra.MaximumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		r := repo()
+		r.FirstParent = firstParent
+
+		from, to := "", ""
+		switch len(args) {
+		case 2:
+			from, to = args[0], args[1]
+		case 1:
+			from = args[0]
+			latest, err := r.Latest(r.ReleaseRegex(latestCalVer()), false)
+			CheckIfError(err)
+			to = latest.Tag()
+		default:
+			groups, err := r.List(r.ReleaseRegex(latestCalVer()), 2, false)
+			CheckIfError(err)
+			if len(groups) < 2 {
+				CheckIfError(fmt.Errorf("need two releases to diff, found %d", len(groups)))
+			}
+			from, to = groups[1].Tag(), groups[0].Tag()
+		}
+
+		d, err := r.Diff(from, to)
+		CheckIfError(err)
+		switch {
+		case machineOutput():
+			printValue(d)
+		case diffMarkdown:
+			CheckIfError(d.WriteMarkdown(os.Stdout))
+		default:
+			CheckIfError(d.WriteText(os.Stdout))
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+	diffCmd.Flags().StringVar(&outputFormat, "output", "", "Machine-readable output format (json or yaml)")
+	diffCmd.Flags().BoolVar(&diffMarkdown, "markdown", false, "Render the diff as Markdown")
+	diffCmd.Flags().BoolVar(&firstParent, "first-parent", false, "Follow only the first parent of merges")
+}
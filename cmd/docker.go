@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	images []string
+	bake   bool
+)
+
+var dockerTagsCmd = &cobra.Command{
+	Use:   "docker-tags",
+	Short: "Print the container image tags for HEAD: version, calendar prefixes, channels and sha-<hash>",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		r := repo()
+		c := channels
+		if len(c) == 0 {
+			var err error
+			c, err = r.Channels()
+			CheckIfError(err)
+		}
+
+		tags, err := r.ImageTags(previewCalVer(), hash, c)
+		CheckIfError(err)
+
+		if bake {
+			e := json.NewEncoder(os.Stdout)
+			e.SetIndent("", "  ")
+			CheckIfError(e.Encode(tags.Bake(images)))
+			return
+		}
+		if machineOutput() {
+			printValue(tags)
+			return
+		}
+		for _, ref := range tags.Refs(images) {
+			fmt.Println(ref)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(dockerTagsCmd)
+	dockerTagsCmd.Flags().StringVar(&outputFormat, "output", "", "Machine-readable output format (json, yaml or ndjson)")
+	dockerTagsCmd.Flags().StringVar(&hash, "hash", "", "Commit to tag images for (default HEAD)")
+	dockerTagsCmd.Flags().StringSliceVar(&images, "image", nil, "Image name to prefix the tags with, eg. ghcr.io/org/app (repeatable)")
+	dockerTagsCmd.Flags().StringSliceVar(&channels, "channel", nil, "Channel tag, eg. stable (default: the channel options of [calver])")
+	dockerTagsCmd.Flags().BoolVar(&bake, "bake", false, "Print a buildx bake file with the tags and OCI labels in the "+
+		"docker-metadata-action target")
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/socialviolation/git-calver/ver"
+	"github.com/spf13/cobra"
+)
+
+var hotfixCmd = &cobra.Command{
+	Use:   "hotfix <base-tag>",
+	Short: "Tag a patch of a past release, eg. 2024.03.15.1, ordered between the release and the next one",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		res, err := repo().Hotfix(ver.TagArgs{
+			Hash:   hash,
+			Push:   push,
+			CV:     latestCalVer(),
+			DryRun: dryRun,
+		}, args[0])
+		if res != nil && err != nil && !machineOutput() {
+			printTagged(res, "Created", "Would create")
+		}
+		CheckIfError(err)
+
+		if machineOutput() {
+			printInfo(res.Info(ver.ActionCreated))
+			return
+		}
+		if short {
+			fmt.Println(res.Tag)
+			return
+		}
+		printTagged(res, "Created", "Would create")
+		printPush(res, "Pushed tag '%s' to", "Would push tag '%s' to")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(hotfixCmd)
+	hotfixCmd.Flags().StringVar(&outputFormat, "output", "", "Machine-readable output format (json, yaml or ndjson)")
+	hotfixCmd.Flags().BoolVarP(&push, "push", "p", false, "Push tag after create")
+	hotfixCmd.Flags().StringVar(&hash, "hash", "", "Commit to tag (default HEAD)")
+	hotfixCmd.Flags().BoolVarP(&short, "short", "s", false, "Output the version number only")
+}
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/template"
+
+	"github.com/socialviolation/git-calver/ver"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	templateText string
+	templateFile string
+	outputFormat string
+	emitFormat   string
+	emitFile     string
+)
+
+const (
+	outputJSON   = "json"
+	outputYAML   = "yaml"
+	outputNDJSON = "ndjson"
+
+	emitGitHub = "github"
+	emitGitLab = "gitlab"
+	emitEnv    = "env"
+)
+
+// machineOutput reports whether --output selected a machine-readable format.
+func machineOutput() bool {
+	return outputFormat != ""
+}
+
+// printInfo writes a single release in the --output format.
+func printInfo(info ver.ReleaseInfo) {
+	printValue(info)
+}
+
+// printValue writes any value in the --output format.
+func printValue(v any) {
+	switch outputFormat {
+	case outputJSON:
+		e := json.NewEncoder(os.Stdout)
+		e.SetIndent("", "  ")
+		CheckIfError(e.Encode(v))
+	case outputNDJSON:
+		CheckIfError(json.NewEncoder(os.Stdout).Encode(v))
+	case outputYAML:
+		e := yaml.NewEncoder(os.Stdout)
+		e.SetIndent(2)
+		CheckIfError(e.Encode(v))
+	default:
+		CheckIfError(fmt.Errorf("unsupported output format '%s' (json, yaml or ndjson)", outputFormat))
+	}
+}
+
+// printInfos writes several releases: a list for json and yaml, one object per line for ndjson.
+func printInfos(infos []ver.ReleaseInfo) {
+	switch outputFormat {
+	case outputJSON:
+		e := json.NewEncoder(os.Stdout)
+		e.SetIndent("", "  ")
+		CheckIfError(e.Encode(infos))
+	case outputNDJSON:
+		e := json.NewEncoder(os.Stdout)
+		for _, info := range infos {
+			CheckIfError(e.Encode(info))
+		}
+	case outputYAML:
+		e := yaml.NewEncoder(os.Stdout)
+		e.SetIndent(2)
+		CheckIfError(e.Encode(infos))
+	default:
+		CheckIfError(fmt.Errorf("unsupported output format '%s' (json, yaml or ndjson)", outputFormat))
+	}
+}
+
+// outputTemplate returns the template selected with --template or --template-file, or nil when neither is set.
+func outputTemplate() *template.Template {
+	if templateText == "" && templateFile == "" {
+		return nil
+	}
+
+	var tmpl *template.Template
+	var err error
+	if templateFile != "" {
+		tmpl, err = ver.ParseTemplateFile(templateFile)
+	} else {
+		tmpl, err = ver.ParseTemplate(templateText)
+	}
+	CheckIfError(err)
+	return tmpl
+}
+
+// resolveHash returns the full hash rev points to in the selected repository, or rev itself when it cannot be
+// resolved (eg. outside a repository with --remote-url).
+func resolveHash(rev string) string {
+	r, err := ver.Open(ver.RepoPath)
+	if err != nil {
+		return rev
+	}
+	co, err := r.ResolveCommit(rev)
+	if err != nil {
+		return rev
+	}
+	return co.Hash.String()
+}
+
+// emit hands the release to the CI system selected with --emit. It reports whether the variables were written
+// to stdout, in which case the command should print nothing else.
+func emit(info ver.ReleaseInfo) bool {
+	switch emitFormat {
+	case "":
+		return false
+	case emitEnv:
+		CheckIfError(ver.WriteExports(os.Stdout, info.Vars()))
+		return true
+	case emitGitHub:
+		path := os.Getenv("GITHUB_OUTPUT")
+		if path == "" {
+			CheckIfError(fmt.Errorf("GITHUB_OUTPUT is not set, --emit github only works inside GitHub Actions"))
+		}
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		CheckIfError(err)
+		defer f.Close()
+		CheckIfError(ver.WriteGitHubOutput(f, info.Vars()))
+	case emitGitLab:
+		f, err := os.Create(emitFile)
+		CheckIfError(err)
+		defer f.Close()
+		CheckIfError(ver.WriteDotenv(f, info.Vars()))
+	default:
+		CheckIfError(fmt.Errorf("unsupported emit target '%s' (github, gitlab or env)", emitFormat))
+	}
+	return false
+}
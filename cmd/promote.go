@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/socialviolation/git-calver/ver"
+	"github.com/spf13/cobra"
+)
+
+var promoteCmd = &cobra.Command{
+	Use:   "promote <tag>",
+	Short: "Point channel aliases (eg. stable) at an existing release, and with --aliases its prefix aliases",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		r := repo()
+		c := channels
+		if len(c) == 0 {
+			var err error
+			c, err = r.Channels()
+			CheckIfError(err)
+		}
+		if len(c) == 0 && !aliases {
+			CheckIfError(fmt.Errorf("nothing to promote, give a --channel or --aliases"))
+		}
+
+		res, err := r.Promote(ver.TagArgs{
+			CV:       latestCalVer(),
+			Tag:      args[0],
+			Push:     push,
+			DryRun:   dryRun,
+			Aliases:  aliases,
+			Channels: c,
+		})
+		if res != nil && !machineOutput() {
+			printAliases(res)
+		}
+		CheckIfError(err)
+
+		if machineOutput() {
+			printInfo(res.Info(ver.ActionPromoted))
+			return
+		}
+		if res.Pushed || res.DryRun {
+			printPush(res, "Force-pushed aliases of '%s' to", "Would force-push aliases of '%s' to")
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(promoteCmd)
+	promoteCmd.Flags().StringVar(&outputFormat, "output", "", "Machine-readable output format (json, yaml or ndjson)")
+	promoteCmd.Flags().BoolVarP(&push, "push", "p", false, "Force-push the aliases")
+	promoteCmd.Flags().BoolVar(&aliases, "aliases", false, "Also move the prefix aliases (eg. 2024 and 2024.03)")
+	promoteCmd.Flags().StringSliceVar(&channels, "channel", nil, "Channel alias to move, eg. stable (default: the channel options of [calver])")
+}
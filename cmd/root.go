@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	colour "github.com/gookit/color"
 	"github.com/socialviolation/git-calver/ver"
@@ -12,11 +13,16 @@ import (
 )
 
 var (
-	dryRun   bool
-	format   string
-	minor    uint
-	micro    uint
-	modifier string
+	dryRun    bool
+	format    string
+	minor     uint
+	micro     uint
+	modifier  string
+	component string
+	paths     []string
+
+	branch         string
+	noBranchPolicy bool
 
 	hash string
 	push bool
@@ -49,9 +55,14 @@ func Execute() {
 }
 
 func init() {
-	rootCmd.PersistentFlags().BoolVarP(&dryRun, "dry-run", "d", false, "Dry run")
+	rootCmd.PersistentFlags().StringVarP(&ver.RepoPath, "repo", "C", "", "Path to the repository (working tree, worktree or bare)")
+	rootCmd.PersistentFlags().BoolVarP(&dryRun, "dry-run", "d", false, "Show what would change without creating, deleting or pushing anything")
 	rootCmd.PersistentFlags().StringVarP(&format, "format", "f", "", "format of calver (YYYY.0M.0D)")
 	rootCmd.PersistentFlags().StringVar(&modifier, "modifier", "", "Modifer (eg. DEV, RC, etc)")
+	rootCmd.PersistentFlags().StringSliceVar(&paths, "path", nil, "Only consider commits touching these paths (default: the path options of [calver \"<component>\"])")
+	rootCmd.PersistentFlags().StringVar(&branch, "branch", "", "Branch whose [calver-branch] policy applies (default: the branch checked out)")
+	rootCmd.PersistentFlags().BoolVar(&noBranchPolicy, "no-branch-policy", false, "Ignore [calver-branch] policies")
+	rootCmd.PersistentFlags().StringVar(&component, "component", "", "Component of a monorepo, tagged as <component>/<version> with its own [calver \"<component>\"] format")
 	rootCmd.PersistentFlags().UintVar(&minor, "minor", 0, "Minor Version")
 	rootCmd.PersistentFlags().UintVar(&micro, "micro", 0, "Micro Version")
 }
@@ -65,25 +76,58 @@ func latestCalVer() *ver.CalVer {
 			Minor:         &minor,
 			Modifier:      modifier,
 			AutoIncrement: autoIncrement,
+			Component:     component,
 		})
 	CheckIfError(err)
 	return f
 }
 
+// nextCalVerArgs returns the CalVer for the next tag. It exits when the branch policy does not allow tagging.
 func nextCalVerArgs() *ver.CalVer {
+	return nextCalVer(false)
+}
+
+// previewCalVer is nextCalVerArgs for commands that do not tag: the branch policy's modifier and
+// auto-increment apply, but a branch where tagging is not allowed is not refused.
+func previewCalVer() *ver.CalVer {
+	return nextCalVer(true)
+}
+
+func nextCalVer(anyBranch bool) *ver.CalVer {
 	f := loadFormat()
 	cv, err := ver.NextCalVer(
 		ver.CalVerArgs{
-			Format:        f,
-			Micro:         &micro,
-			Minor:         &minor,
-			Modifier:      modifier,
-			AutoIncrement: autoIncrement,
+			Format:             f,
+			Micro:              &micro,
+			Minor:              &minor,
+			Modifier:           modifier,
+			AutoIncrement:      autoIncrement,
+			RemoteURL:          remoteURL,
+			Component:          component,
+			Branch:             branch,
+			IgnoreBranchPolicy: noBranchPolicy,
+			AnyBranch:          anyBranch,
 		})
+	if errors.Is(err, ver.ErrBranchNotAllowed) {
+		err = fmt.Errorf("%w, use --no-branch-policy to tag anyway", err)
+	}
 	CheckIfError(err)
 	return cv
 }
 
+// repo opens the repository selected with --repo, restricted to the paths of --component or --path.
+func repo() *ver.Repo {
+	r, err := ver.Open(ver.RepoPath)
+	CheckIfError(err)
+
+	r.Paths = paths
+	if len(r.Paths) == 0 {
+		r.Paths, err = r.ComponentPaths(component)
+		CheckIfError(err)
+	}
+	return r
+}
+
 // CheckIfError should be used to naively panics if an error is not nil.
 func CheckIfError(err error) {
 	if err == nil {
@@ -132,9 +176,9 @@ func getFormat() (*ver.Format, string, error) {
 		return f, "environment", nil
 	}
 
-	gitConf, a, err := ver.GetRepoFormat()
+	gitConf, a, err := ver.GetComponentFormat(component)
 	if err != nil {
-		if err.Error() == "[calver] not set" {
+		if errors.Is(err, ver.ErrFormatNotSet) {
 			return nil, "gitconfig", fmt.Errorf("format not set")
 		}
 		return nil, "gitconfig", err
@@ -1,8 +1,10 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	colour "github.com/gookit/color"
@@ -17,6 +19,15 @@ var (
 	autoIncrement     bool
 	autoIncrementFlag bool
 	short             bool
+	atomic            bool
+	ifUntagged        bool
+	requireChanges    bool
+	force             bool
+	aliases           bool
+	channels          []string
+	retries           int
+	remoteURL         string
+	firstParent       bool
 )
 
 var latestTagCmd = &cobra.Command{
@@ -24,14 +35,32 @@ var latestTagCmd = &cobra.Command{
 	Short: "Get latest tag matching the provided format",
 	Run: func(cmd *cobra.Command, args []string) {
 		f := latestCalVer()
-		tag, err := ver.LatestTag(f.Regex(), changelog)
-		CheckIfError(err)
-
-		if tag == nil {
+		var tag *ver.CalVerTagGroup
+		var err error
+		if remoteURL != "" {
+			tag, err = ver.LatestRemoteTag(remoteURL, f.Regex())
+		} else {
+			r := repo()
+			r.FirstParent = firstParent
+			tag, err = r.Latest(r.ReleaseRegex(f), changelog)
+		}
+		if errors.Is(err, ver.ErrNoTags) && !machineOutput() {
 			fmt.Printf("No tag found.\n")
 			return
 		}
+		CheckIfError(err)
 
+		if emit(tag.Info()) {
+			return
+		}
+		if machineOutput() {
+			printInfo(tag.Info())
+			return
+		}
+		if tmpl := outputTemplate(); tmpl != nil {
+			CheckIfError(tag.Render(os.Stdout, tmpl))
+			return
+		}
 		tag.Print(os.Stdout, noColour, short)
 	},
 }
@@ -40,10 +69,38 @@ var nextTagCommand = &cobra.Command{
 	Use:   "next",
 	Short: "Output what the next calver tag will be",
 	Run: func(cmd *cobra.Command, args []string) {
-		cv := nextCalVerArgs()
-		tag, err := cv.Version(time.Now())
+		// next only previews the tag, so a branch where tagging is not allowed is not refused.
+		cv := previewCalVer()
+		var tag string
+		var err error
+		if remoteURL != "" {
+			tag, err = cv.Version(time.Now())
+		} else {
+			tag, _, err = repo().NextVersion(cv)
+		}
 		CheckIfError(err)
+		if requireChanges {
+			r := repo()
+			CheckIfError(r.RequireChanges(r.ReleaseRegex(cv), hash))
+		}
 
+		info := ver.NewReleaseInfo(tag)
+		info.Hash = resolveHash(hash)
+		if emit(info) {
+			return
+		}
+		if machineOutput() {
+			printInfo(info)
+			return
+		}
+		if tmpl := outputTemplate(); tmpl != nil {
+			CheckIfError(tmpl.Execute(os.Stdout, ver.PendingRelease{
+				Version: tag,
+				Hash:    resolveHash(hash),
+				When:    time.Now(),
+			}))
+			return
+		}
 		if short {
 			fmt.Println(tag)
 		} else {
@@ -58,15 +115,36 @@ var listTagCmd = &cobra.Command{
 	Short: "Will list all CalVer tags matching the provided format",
 	Run: func(cmd *cobra.Command, args []string) {
 		f := latestCalVer()
-		tags, err := ver.ListTags(f.Regex(), limit, changelog)
+		var tags []*ver.CalVerTagGroup
+		var err error
+		if remoteURL != "" {
+			tags, err = ver.ListRemoteTags(remoteURL, f.Regex(), limit)
+		} else {
+			r := repo()
+			r.FirstParent = firstParent
+			tags, err = r.List(r.ReleaseRegex(f), limit, changelog)
+		}
 		CheckIfError(err)
 
+		if machineOutput() {
+			infos := make([]ver.ReleaseInfo, 0, len(tags))
+			for _, tag := range tags {
+				infos = append(infos, tag.Info())
+			}
+			printInfos(infos)
+			return
+		}
 		if len(tags) == 0 {
 			fmt.Printf("No tags found.\n")
 			return
 		}
 
+		tmpl := outputTemplate()
 		for _, tag := range tags {
+			if tmpl != nil {
+				CheckIfError(tag.Render(os.Stdout, tmpl))
+				continue
+			}
 			tag.Print(os.Stdout, noColour, short)
 		}
 	},
@@ -87,21 +165,60 @@ var tagCmd = &cobra.Command{
 			tag = args[0]
 		}
 
-		if tag == "" {
-			tag, _ = cv.Version(time.Now())
+		var res *ver.TagResult
+		var err error
+		if atomic {
+			res, err = repo().TagAndPush(ver.TagArgs{
+				Hash:           hash,
+				CV:             cv,
+				Tag:            tag,
+				DryRun:         dryRun,
+				IfUntagged:     ifUntagged,
+				RequireChanges: requireChanges,
+				Force:          force,
+				Aliases:        aliases,
+				Channels:       aliasChannels(),
+			}, retries)
+		} else {
+			res, err = repo().Tag(ver.TagArgs{
+				Hash:           hash,
+				Push:           push,
+				CV:             cv,
+				Tag:            tag,
+				DryRun:         dryRun,
+				IfUntagged:     ifUntagged,
+				RequireChanges: requireChanges,
+				Force:          force,
+				Aliases:        aliases,
+				Channels:       aliasChannels(),
+			})
+			if res != nil && err != nil && !machineOutput() {
+				printTagged(res, "Created", "Would create")
+			}
+		}
+		if errors.Is(err, ver.ErrVersionNotNewer) {
+			err = fmt.Errorf("%w, use --force to tag anyway", err)
 		}
-		commit, err := ver.TagNext(ver.TagArgs{
-			Hash: hash,
-			Push: push,
-			CV:   cv,
-			Tag:  tag,
-		})
 		CheckIfError(err)
+
+		if emit(res.Info(ver.ActionCreated)) {
+			return
+		}
+		if machineOutput() {
+			printInfo(res.Info(ver.ActionCreated))
+			return
+		}
 		if short {
-			fmt.Println(tag)
-		} else {
-			fmt.Printf("Created tag '%s' (hash %s)\n", tag, commit)
+			fmt.Println(res.Tag)
+			return
+		}
+		if res.Existing {
+			fmt.Printf("Tag '%s' already on hash %s\n", res.Tag, res.ShortHash())
+			return
 		}
+		printTagged(res, "Created", "Would create")
+		printPush(res, "Pushed tag '%s' to", "Would push tag '%s' to")
+		printAliases(res)
 	},
 }
 
@@ -120,19 +237,30 @@ var retagCmd = &cobra.Command{
 			tag, _ = cv.Version(time.Now())
 		}
 
-		exists := ver.TagExists(tag)
-		if !exists {
+		r := repo()
+		if !r.TagExists(tag) {
 			CheckIfError(fmt.Errorf("tag '%s' does not exist", tag))
 		}
 
-		commit, err := ver.Retag(ver.TagArgs{
-			Hash: hash,
-			Push: push,
-			CV:   cv,
-			Tag:  tag,
+		old, res, err := r.Retag(ver.TagArgs{
+			Hash:   hash,
+			Push:   push,
+			CV:     cv,
+			Tag:    tag,
+			DryRun: dryRun,
 		})
+		if old != nil && !machineOutput() {
+			printTagged(old, "Deleted", "Would delete")
+		}
 		CheckIfError(err)
-		fmt.Printf("Created tag '%s' (hash %s)\n", tag, commit)
+		if machineOutput() {
+			info := res.Info(ver.ActionRetagged)
+			info.PreviousHash = old.Hash
+			printInfo(info)
+			return
+		}
+		printTagged(res, "Created", "Would create")
+		printPush(res, "Pushed tag '%s' to", "Would push tag '%s' to")
 	},
 }
 
@@ -140,7 +268,7 @@ var untagCmd = &cobra.Command{
 	Use:   "untag",
 	Short: "untag",
 	Run: func(cmd *cobra.Command, args []string) {
-		cv := nextCalVerArgs()
+		cv := latestCalVer()
 
 		tag := ""
 		if len(args) > 0 {
@@ -151,49 +279,136 @@ var untagCmd = &cobra.Command{
 			tag, _ = cv.Version(time.Now())
 		}
 
-		exists := ver.TagExists(tag)
-		if !exists {
+		r := repo()
+		if !r.TagExists(tag) {
 			CheckIfError(fmt.Errorf("tag '%s' does not exist", tag))
 		}
 
-		err := ver.Untag(ver.TagArgs{
-			Hash: hash,
-			Push: push,
-			CV:   cv,
-			Tag:  tag,
+		res, err := r.Untag(ver.TagArgs{
+			Hash:   hash,
+			Push:   push,
+			CV:     cv,
+			Tag:    tag,
+			DryRun: dryRun,
 		})
 		CheckIfError(err)
+		if machineOutput() {
+			printInfo(res.Info(ver.ActionDeleted))
+			return
+		}
+		printTagged(res, "Deleted", "Would delete")
+		printPush(res, "Deleted tag '%s' on", "Would delete tag '%s' on")
 	},
 }
 
+// printTagged reports a created or deleted tag, using the planned wording for a dry run.
+func printTagged(res *ver.TagResult, done, planned string) {
+	verb := done
+	if res.DryRun {
+		verb = planned
+	}
+	fmt.Printf("%s tag '%s' (hash %s)\n", verb, res.Tag, res.ShortHash())
+}
+
+// aliasChannels returns the channels given with --channel, or the channels configured for the repository.
+func aliasChannels() []string {
+	if len(channels) > 0 || !aliases {
+		return channels
+	}
+	c, err := repo().Channels()
+	CheckIfError(err)
+	return c
+}
+
+// printAliases reports the alias tags moved to a tag.
+func printAliases(res *ver.TagResult) {
+	verb := "Moved"
+	if res.DryRun {
+		verb = "Would move"
+	}
+	for _, a := range res.Aliases {
+		fmt.Printf("%s alias '%s' to %s (hash %s)\n", verb, a, res.Tag, res.ShortHash())
+	}
+}
+
+// printPush reports the push of a tag to origin. For a dry run, it also lists the remote URL and the hooks
+// git would run.
+func printPush(res *ver.TagResult, done, planned string) {
+	if res.DryRun && res.Remote != "" {
+		fmt.Printf(planned+" %s", res.Tag, res.Remote)
+		if res.RemoteURL != "" {
+			fmt.Printf(" (%s)", res.RemoteURL)
+		}
+		fmt.Println()
+		if len(res.Hooks) > 0 {
+			fmt.Printf("Would run hooks: %s\n", strings.Join(res.Hooks, ", "))
+		}
+		return
+	}
+	if res.Pushed {
+		fmt.Printf(done+" %s\n", res.Tag, res.Remote)
+	}
+}
+
 func init() {
 	rootCmd.AddCommand(listTagCmd)
+	listTagCmd.Flags().StringVar(&outputFormat, "output", "", "Machine-readable output format (json, yaml or ndjson)")
 	listTagCmd.Flags().BoolVar(&noColour, "no-colour", false, "Disable colour output")
 	listTagCmd.Flags().BoolVar(&changelog, "changeLog", true, "Include changelog")
+	listTagCmd.Flags().BoolVar(&firstParent, "first-parent", false, "Follow only the first parent of merges in the changelog")
 	listTagCmd.Flags().IntVarP(&limit, "limit", "l", 5, "Limit number of results (based on hashes)")
 	listTagCmd.Flags().BoolVarP(&short, "short", "s", false, "Output the version number only")
+	listTagCmd.Flags().StringVar(&remoteURL, "remote-url", "", "Read tags from a remote URL instead of the local repository")
+	listTagCmd.Flags().StringVar(&templateText, "template", "", "Render output with a Go text/template")
+	listTagCmd.Flags().StringVar(&templateFile, "template-file", "", "Render output with a Go text/template read from a file")
 
 	rootCmd.AddCommand(latestTagCmd)
+	latestTagCmd.Flags().StringVar(&outputFormat, "output", "", "Machine-readable output format (json, yaml or ndjson)")
+	latestTagCmd.Flags().StringVar(&emitFormat, "emit", "", "Hand the version to CI: github ($GITHUB_OUTPUT), gitlab (dotenv file) or env (export lines)")
+	latestTagCmd.Flags().StringVar(&emitFile, "emit-file", "calver.env", "Dotenv file written by --emit gitlab")
 	latestTagCmd.Flags().BoolVar(&noColour, "no-colour", false, "Disable colour output")
 	latestTagCmd.Flags().BoolVar(&changelog, "changeLog", true, "Include changelog")
+	latestTagCmd.Flags().BoolVar(&firstParent, "first-parent", false, "Follow only the first parent of merges in the changelog")
 	latestTagCmd.Flags().BoolVarP(&short, "short", "s", false, "Output the version number only")
+	latestTagCmd.Flags().StringVar(&remoteURL, "remote-url", "", "Read tags from a remote URL instead of the local repository")
+	latestTagCmd.Flags().StringVar(&templateText, "template", "", "Render output with a Go text/template")
+	latestTagCmd.Flags().StringVar(&templateFile, "template-file", "", "Render output with a Go text/template read from a file")
 
 	rootCmd.AddCommand(tagCmd)
+	tagCmd.Flags().StringVar(&outputFormat, "output", "", "Machine-readable output format (json, yaml or ndjson)")
+	tagCmd.Flags().StringVar(&emitFormat, "emit", "", "Hand the version to CI: github ($GITHUB_OUTPUT), gitlab (dotenv file) or env (export lines)")
+	tagCmd.Flags().StringVar(&emitFile, "emit-file", "calver.env", "Dotenv file written by --emit gitlab")
 	tagCmd.Flags().BoolVarP(&push, "push", "p", false, "Push tag after create")
 	tagCmd.Flags().BoolVarP(&autoIncrementFlag, "auto-increment", "i", false, "Adds an auto-incremented modifier, based off previous latest release")
 	tagCmd.Flags().StringVar(&hash, "hash", "", "Override Hash")
 	tagCmd.Flags().BoolVarP(&short, "short", "s", false, "Output the version number only")
+	tagCmd.Flags().BoolVar(&atomic, "atomic", false, "Create and push the tag, retrying with the next auto-increment if the remote already has it")
+	tagCmd.Flags().IntVar(&retries, "retries", 3, "Number of retries for --atomic when the remote rejects the tag")
+	tagCmd.Flags().BoolVar(&ifUntagged, "if-untagged", false, "Reuse the calver tag already on the commit instead of creating another")
+	tagCmd.Flags().BoolVar(&aliases, "aliases", false, "Move alias tags (eg. 2024 and 2024.03, and channels) to the new version when it is the newest")
+	tagCmd.Flags().StringSliceVar(&channels, "channel", nil, "Channel alias to move with --aliases, eg. stable (default: the channel options of [calver])")
+	tagCmd.Flags().BoolVar(&force, "force", false, "Create the tag even if it does not order above the latest tag")
+	tagCmd.Flags().BoolVar(&requireChanges, "require-changes", false, "Fail when there are no commits since the latest tag")
 
 	rootCmd.AddCommand(retagCmd)
+	retagCmd.Flags().StringVar(&outputFormat, "output", "", "Machine-readable output format (json, yaml or ndjson)")
 	retagCmd.Flags().BoolVarP(&push, "push", "p", false, "Push tag after update")
 	retagCmd.Flags().StringVar(&hash, "hash", "", "Override Hash")
 
 	rootCmd.AddCommand(untagCmd)
+	untagCmd.Flags().StringVar(&outputFormat, "output", "", "Machine-readable output format (json, yaml or ndjson)")
 	untagCmd.Flags().BoolVarP(&push, "push", "p", false, "Push tag after delete")
 	untagCmd.Flags().StringVar(&hash, "hash", "", "Override Hash")
 
 	rootCmd.AddCommand(nextTagCommand)
+	nextTagCommand.Flags().StringVar(&outputFormat, "output", "", "Machine-readable output format (json, yaml or ndjson)")
+	nextTagCommand.Flags().StringVar(&emitFormat, "emit", "", "Hand the version to CI: github ($GITHUB_OUTPUT), gitlab (dotenv file) or env (export lines)")
+	nextTagCommand.Flags().StringVar(&emitFile, "emit-file", "calver.env", "Dotenv file written by --emit gitlab")
 	nextTagCommand.Flags().StringVar(&hash, "hash", "HEAD", "Override Hash")
 	nextTagCommand.Flags().BoolVarP(&short, "short", "s", false, "Output the version number only")
 	nextTagCommand.Flags().BoolVarP(&autoIncrementFlag, "auto-increment", "i", false, "Adds an auto-incremented modifier, based off previous latest release")
+	nextTagCommand.Flags().BoolVar(&requireChanges, "require-changes", false, "Fail when there are no commits since the latest tag")
+	nextTagCommand.Flags().StringVar(&remoteURL, "remote-url", "", "Read tags from a remote URL instead of the local repository")
+	nextTagCommand.Flags().StringVar(&templateText, "template", "", "Render output with a Go text/template")
+	nextTagCommand.Flags().StringVar(&templateFile, "template-file", "", "Render output with a Go text/template read from a file")
 }
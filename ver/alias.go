@@ -0,0 +1,154 @@
+package ver
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// PrefixAliases returns the alias tags derived from the calendar segments of version, eg. 2024 and 2024.03 for
+// 2024.03.15-2. A component prefix is kept, so api/2024.03.15 gives api/2024 and api/2024.03.
+func PrefixAliases(version string) []string {
+	component, v := SplitComponent(version)
+	calendar, _, _ := strings.Cut(v, "-")
+	segments := strings.Split(calendar, ".")
+
+	aliases := make([]string, 0, len(segments))
+	for i := 1; i < len(segments); i++ {
+		aliases = append(aliases, withComponent(component, strings.Join(segments[:i], ".")))
+	}
+	return aliases
+}
+
+// ChannelAliases returns the alias tags of named channels, such as stable or latest, for version. A component
+// prefix is kept, so api/2024.03.15 on stable gives api/stable.
+func ChannelAliases(version string, channels []string) []string {
+	component, _ := SplitComponent(version)
+	aliases := make([]string, 0, len(channels))
+	for _, c := range channels {
+		aliases = append(aliases, withComponent(component, c))
+	}
+	return aliases
+}
+
+// newestWith reports whether v is at least as new as the newest final release of groups whose tag starts with
+// prefix. An empty prefix matches every group. Pre-releases are skipped, as they never carry aliases. groups
+// must be sorted newest first, as List returns them.
+func newestWith(groups []*CalVerTagGroup, v, prefix string) bool {
+	for _, g := range groups {
+		if preRelease(g.Tag()) {
+			continue
+		}
+		if prefix == "" || strings.HasPrefix(g.Tag(), prefix+".") || strings.HasPrefix(g.Tag(), prefix+"-") {
+			return CompareVersions(v, g.Tag()) >= 0
+		}
+	}
+	return true
+}
+
+// preRelease reports whether v carries a pre-release modifier, as in 2024.03.15-dev.2 or 2024.03.15-RC1.
+func preRelease(v string) bool {
+	_, m, err := ParseVersion(v)
+	return err == nil && IsPreRelease(m)
+}
+
+func withComponent(component, name string) string {
+	if component == "" {
+		return name
+	}
+	return component + "/" + name
+}
+
+// Channels returns the channels listed as channel options in the [calver] section of the repository config.
+func (r *Repo) Channels() ([]string, error) {
+	conf, err := r.git.Config()
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve config: %w", err)
+	}
+	if !conf.Raw.HasSection("calver") {
+		return nil, nil
+	}
+	return conf.Raw.Section("calver").Options.GetAll("channel"), nil
+}
+
+// Promote points aliases at the existing release args.Tag: its channels (args.Channels) and, when args.Aliases
+// is set, its prefix aliases. Unlike tagging, aliases are moved even when newer releases exist, so a channel can
+// be rolled back. Aliases are force-pushed when args.Push is set.
+func (r *Repo) Promote(args TagArgs) (*TagResult, error) {
+	co, err := r.commitForTag(plumbing.NewTagReferenceName(args.Tag).String())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrTagNotFound, args.Tag)
+	}
+
+	res := &TagResult{Tag: args.Tag, Hash: co.Hash.String(), When: co.Author.When, DryRun: args.DryRun}
+	if args.Push {
+		r.describePush(res)
+	}
+	res.Aliases, err = r.moveAliases(args, args.Tag, co, true)
+	if err != nil {
+		return res, err
+	}
+	res.Pushed = args.Push && !args.DryRun
+	return res, nil
+}
+
+// moveAliases points the aliases of v at co and returns the aliases it moved. Aliases that look like releases
+// of args.CV are never touched. Unless force is set, an alias only moves when v is the newest final release
+// carrying it, so a hotfix of an old release leaves 2024 and stable alone, and pre-releases such as dev
+// builds move none.
+func (r *Repo) moveAliases(args TagArgs, v string, co *object.Commit, force bool) ([]string, error) {
+	if !force && preRelease(v) {
+		return make([]string, 0), nil
+	}
+	var groups []*CalVerTagGroup
+	isRelease := func(string) bool { return false }
+	if args.CV != nil {
+		reg := (&CalVer{Component: args.CV.Component, Format: args.CV.Format}).PatchRegex()
+		isRelease = reg.MatchString
+		var err error
+		if groups, err = r.List(reg, math.MaxInt, false); err != nil {
+			return nil, err
+		}
+	}
+
+	candidates := make([]string, 0)
+	if args.Aliases {
+		for _, a := range PrefixAliases(v) {
+			if force || newestWith(groups, v, a) {
+				candidates = append(candidates, a)
+			}
+		}
+	}
+	if force || newestWith(groups, v, "") {
+		candidates = append(candidates, ChannelAliases(v, args.Channels)...)
+	}
+
+	moved := make([]string, 0, len(candidates))
+	for _, alias := range candidates {
+		if alias == v || isRelease(alias) {
+			continue
+		}
+		moved = append(moved, alias)
+		if args.DryRun {
+			continue
+		}
+
+		if r.TagExists(alias) {
+			if err := r.tags.DeleteTag(alias); err != nil {
+				return moved, fmt.Errorf("could not move tag '%s': %w", alias, err)
+			}
+		}
+		if err := r.tags.CreateTag(alias, co.Hash); err != nil {
+			return moved, fmt.Errorf("could not move tag '%s': %w", alias, err)
+		}
+		if args.Push {
+			if err := r.tags.ForcePush(alias); err != nil {
+				return moved, err
+			}
+		}
+	}
+	return moved, nil
+}
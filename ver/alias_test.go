@@ -0,0 +1,125 @@
+package ver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrefixAliases(t *testing.T) {
+	assert.Equal(t, []string{"2024", "2024.03"}, PrefixAliases("2024.03.15-2"))
+	assert.Equal(t, []string{"api/2024", "api/2024.03"}, PrefixAliases("api/2024.03.15"))
+	assert.Equal(t, []string{"api/stable"}, ChannelAliases("api/2024.03.15", []string{"stable"}))
+}
+
+func TestRepoAliases(t *testing.T) {
+	r, s := newTestRepo(t)
+	f, err := NewFormat("YYYY.0M.0D")
+	require.NoError(t, err)
+	cv := &CalVer{Format: f}
+	day := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+
+	c1 := testCommit(t, r, "first", day)
+	res, err := r.Tag(TagArgs{CV: cv, Tag: "2024.03.15", Aliases: true, Channels: []string{"stable"}, Push: true})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2024", "2024.03", "stable"}, res.Aliases)
+
+	c2 := testCommit(t, r, "second", day.Add(24*time.Hour))
+	_, err = r.Tag(TagArgs{CV: cv, Tag: "2024.04.01", Aliases: true, Channels: []string{"stable"}, Push: true})
+	require.NoError(t, err)
+
+	// An older release only moves the aliases it is the newest for.
+	testTag(t, s, "2024.03.01", c1)
+	res, err = r.Tag(TagArgs{CV: cv, Tag: "2024.03.16", Hash: c1.String(), Aliases: true, Channels: []string{"stable"}, Force: true})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2024.03"}, res.Aliases)
+
+	co, err := r.commitForTag("stable")
+	require.NoError(t, err)
+	assert.Equal(t, c2, co.Hash)
+	remote, err := s.Origin.Reference(plumbing.NewTagReferenceName("2024"))
+	require.NoError(t, err)
+	assert.Equal(t, c2, remote.Hash())
+
+	res, err = r.Promote(TagArgs{CV: cv, Tag: "2024.03.15", Channels: []string{"stable"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"stable"}, res.Aliases)
+	co, err = r.commitForTag("stable")
+	require.NoError(t, err)
+	assert.Equal(t, c1, co.Hash)
+}
+
+func TestRepoAliasesPreRelease(t *testing.T) {
+	r, _ := newTestRepo(t)
+	f, err := NewFormat("YYYY.0M.0D")
+	require.NoError(t, err)
+	cv := &CalVer{Format: f}
+	day := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+
+	c1 := testCommit(t, r, "release", day)
+	_, err = r.Tag(TagArgs{CV: cv, Tag: "2024.03.15", Aliases: true, Channels: []string{"latest"}})
+	require.NoError(t, err)
+
+	// A later dev build moves no aliases, and does not hold them back from the next final release.
+	testCommit(t, r, "dev build", day.Add(24*time.Hour))
+	res, err := r.Tag(TagArgs{CV: cv, Tag: "2024.03.16-dev.2", Aliases: true, Channels: []string{"latest"}})
+	require.NoError(t, err)
+	assert.Empty(t, res.Aliases)
+	for _, alias := range []string{"2024", "2024.03", "latest"} {
+		co, err := r.commitForTag(alias)
+		require.NoError(t, err)
+		assert.Equal(t, c1, co.Hash, alias)
+	}
+
+	c3 := testCommit(t, r, "hotfix", day.Add(2*time.Hour), c1)
+	res, err = r.Tag(TagArgs{CV: cv, Tag: "2024.03.15.1", Hash: c3.String(), Aliases: true, Channels: []string{"latest"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2024", "2024.03", "latest"}, res.Aliases)
+}
+
+func TestRepoAliasesRetry(t *testing.T) {
+	r, s := newTestRepo(t)
+	base := testCommit(t, r, "release", time.Now())
+	testTag(t, s, "stable", base)
+	require.NoError(t, s.Push("stable", false))
+	other := testCommit(t, r, "other job", time.Now())
+	head := testCommit(t, r, "this job", time.Now())
+
+	cv, err := r.Next(CalVerArgs{RawFormat: "YYYY.0M.0D", AutoIncrement: true})
+	require.NoError(t, err)
+	first, err := cv.Version(time.Now())
+	require.NoError(t, err)
+
+	// Another job released first and moved stable to it, so the local stable is stale.
+	for _, name := range []string{first, "stable"} {
+		require.NoError(t, s.Origin.SetReference(plumbing.NewHashReference(plumbing.NewTagReferenceName(name), other)))
+	}
+
+	res, err := r.TagAndPush(TagArgs{CV: cv, Aliases: true, Channels: []string{"stable"}}, 3)
+	require.NoError(t, err)
+	assert.Equal(t, cv.Format.Version(time.Now())+"-2", res.Tag)
+	assert.Contains(t, res.Aliases, "stable")
+
+	for _, name := range []string{first, "stable"} {
+		remote, err := s.Origin.Reference(plumbing.NewTagReferenceName(name))
+		require.NoError(t, err)
+		local, err := r.Git().Reference(plumbing.NewTagReferenceName(name), false)
+		require.NoError(t, err)
+		if name == first {
+			assert.Equal(t, other, local.Hash(), name)
+		} else {
+			assert.Equal(t, head, local.Hash(), name)
+		}
+		assert.Equal(t, local.Hash(), remote.Hash(), name)
+	}
+
+	// A fetch replaces local tags that moved on origin, as git fetch does with a forced refspec.
+	require.NoError(t, s.Origin.SetReference(plumbing.NewHashReference(plumbing.NewTagReferenceName("stable"), base)))
+	require.NoError(t, s.Fetch())
+	local, err := r.Git().Reference(plumbing.NewTagReferenceName("stable"), false)
+	require.NoError(t, err)
+	assert.Equal(t, base, local.Hash())
+}
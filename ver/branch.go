@@ -0,0 +1,207 @@
+package ver
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// ReleaseBranchPrefix starts the name of every release branch, eg. release/2024.03.
+const ReleaseBranchPrefix = "release/"
+
+// ErrBranchExists is returned when creating a release branch that is already present.
+var ErrBranchExists = errors.New("branch already exists")
+
+// BranchResult describes a release branch created by a Repo.
+type BranchResult struct {
+	// Branch is the short name of the branch, eg. release/2024.03.
+	Branch string
+	// Version is the release the branch maintains; its tags are patch versions of it.
+	Version string
+	// Hash is the full hash of the commit the branch starts from.
+	Hash string
+	// DryRun is set when the branch was only planned, and nothing was written.
+	DryRun bool
+}
+
+// ShortHash returns the abbreviated commit hash.
+func (b *BranchResult) ShortHash() string {
+	if len(b.Hash) < 7 {
+		return b.Hash
+	}
+	return b.Hash[:7]
+}
+
+// ReleaseVersion returns the version a release branch maintains: the calendar version of cv at t, without a
+// modifier or auto-increment, eg. 2024.03 or api/2024.03.
+func ReleaseVersion(cv *CalVer, t time.Time) (string, error) {
+	base := *cv
+	base.Modifier = ""
+	base.AutoIncrement = false
+	base.Increment = 0
+	return base.Version(t)
+}
+
+// CreateReleaseBranch creates release/<version> on the commit given by rev (HEAD when empty), where version is
+// the current calendar version of cv. With dryRun set, the branch is validated and described but not created.
+func (r *Repo) CreateReleaseBranch(cv *CalVer, rev string, dryRun bool) (*BranchResult, error) {
+	co, err := r.ResolveCommit(rev)
+	if err != nil {
+		return nil, err
+	}
+	v, err := ReleaseVersion(cv, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	res := &BranchResult{Branch: ReleaseBranchPrefix + v, Version: v, Hash: co.Hash.String(), DryRun: dryRun}
+	name := plumbing.NewBranchReferenceName(res.Branch)
+	if _, err := r.git.Reference(name, false); err == nil {
+		return nil, fmt.Errorf("%w: %s", ErrBranchExists, res.Branch)
+	}
+	if dryRun {
+		return res, nil
+	}
+
+	if err := r.git.Storer.SetReference(plumbing.NewHashReference(name, co.Hash)); err != nil {
+		return nil, fmt.Errorf("could not create branch '%s': %w", res.Branch, err)
+	}
+	return res, nil
+}
+
+// ReleaseBranch returns the version maintained by the release branch checked out at HEAD, if any. Release
+// branches of other components than cv's are ignored.
+func (r *Repo) ReleaseBranch(cv *CalVer) (string, bool) {
+	head, err := r.git.Head()
+	if err != nil || !head.Name().IsBranch() {
+		return "", false
+	}
+	v, ok := strings.CutPrefix(head.Name().Short(), ReleaseBranchPrefix)
+	if !ok {
+		return "", false
+	}
+	if component, _ := SplitComponent(v); component != cv.Component {
+		return "", false
+	}
+	return v, true
+}
+
+// ReleaseRegex returns the regex for the releases of cv relevant at HEAD. On a release branch of cv's
+// component, these are the release the branch maintains and its patch versions, eg. 2024.03 and 2024.03.N;
+// everywhere else, the tags matching Regex and the hotfixes of those tags, eg. 2024.03.15 and 2024.03.15.N.
+// Patches are only matched for tags that exist, so a day tag such as 2024.03.15 is not read as a patch of
+// the format YYYY.0M unless 2024.03 was released.
+func (r *Repo) ReleaseRegex(cv *CalVer) *regexp.Regexp {
+	if base, ok := r.ReleaseBranch(cv); ok {
+		return regexp.MustCompile(`^` + regexp.QuoteMeta(base) + `(\.[0-9]+)?$`)
+	}
+
+	reg := cv.Regex()
+	refs, err := r.tags.Tags()
+	if err != nil {
+		return reg
+	}
+	bases := make([]string, 0)
+	for _, ref := range refs {
+		if tag := ref.Name().Short(); reg.MatchString(tag) {
+			bases = append(bases, regexp.QuoteMeta(tag))
+		}
+	}
+	if len(bases) == 0 {
+		return reg
+	}
+	return regexp.MustCompile(reg.String() + `|^(` + strings.Join(bases, "|") + `)\.[0-9]+$`)
+}
+
+// NextPatch returns the next patch version of base, one above the highest base.N tag, eg. 2024.03.3 after
+// 2024.03.2. The first patch is base.1.
+func (r *Repo) NextPatch(base string) (string, error) {
+	refs, err := r.tags.Tags()
+	if err != nil {
+		return "", fmt.Errorf("could not find tags: %w", err)
+	}
+
+	reg := regexp.MustCompile(`^` + regexp.QuoteMeta(base) + `\.([0-9]+)$`)
+	patch := 0
+	for _, ref := range refs {
+		m := reg.FindStringSubmatch(ref.Name().Short())
+		if m == nil {
+			continue
+		}
+		if n, err := strconv.Atoi(m[1]); err == nil && n > patch {
+			patch = n
+		}
+	}
+	return fmt.Sprintf("%s.%d", base, patch+1), nil
+}
+
+// NextVersion returns the version the next tag for cv gets: the next patch version when HEAD is on a release
+// branch, otherwise the calendar version for now. It reports whether the version is a patch.
+func (r *Repo) NextVersion(cv *CalVer) (string, bool, error) {
+	if base, ok := r.ReleaseBranch(cv); ok {
+		v, err := r.NextPatch(base)
+		return v, true, err
+	}
+	v, err := cv.Version(time.Now())
+	return v, false, err
+}
+
+// HotfixVersion returns the next hotfix version of the base tag: a patch segment is appended to base, or
+// incremented when base is itself a hotfix, eg. 2024.03.15 and 2024.03.15.1 both lead to 2024.03.15.1 and
+// then 2024.03.15.2. A base with a modifier gets a patch of its modifier, eg. 2024.03.15-2.1. Hotfixes order
+// after their base and before the next calendar release.
+func (r *Repo) HotfixVersion(f *Format, base string) (string, error) {
+	_, version := SplitComponent(base)
+	segments, modifier, err := ParseVersion(version)
+	if err != nil {
+		return "", err
+	}
+
+	root := base
+	if modifier != "" {
+		if i := strings.LastIndex(modifier, "."); i >= 0 {
+			root = strings.TrimSuffix(base, modifier[i:])
+		}
+	} else if len(segments) > f.Segments() {
+		root = base[:strings.LastIndex(base, ".")]
+	}
+	return r.NextPatch(root)
+}
+
+// Hotfix tags the commit given by args.Hash (HEAD when empty) with the next hotfix version of the base tag. The
+// commit must have the base tag in its history. The hotfix is pushed when args.Push is set, and only planned
+// with args.DryRun.
+func (r *Repo) Hotfix(args TagArgs, base string) (*TagResult, error) {
+	baseCommit, err := r.commitForTag(plumbing.NewTagReferenceName(base).String())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrTagNotFound, base)
+	}
+	co, err := r.ResolveCommit(args.Hash)
+	if err != nil {
+		return nil, err
+	}
+	if co.Hash != baseCommit.Hash {
+		ok, err := baseCommit.IsAncestor(co)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, fmt.Errorf("commit %s is not based on %s", co.Hash.String()[:7], base)
+		}
+	}
+
+	args.Tag, err = r.HotfixVersion(args.CV.Format, base)
+	if err != nil {
+		return nil, err
+	}
+	// Hotfixes deliberately order below newer releases.
+	args.Force = true
+	args.IfUntagged = false
+	args.RequireChanges = false
+	return r.Tag(args)
+}
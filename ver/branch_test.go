@@ -0,0 +1,150 @@
+package ver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepoReleaseBranch(t *testing.T) {
+	r, s := newTestRepo(t)
+	cv, err := NewCalVer(CalVerArgs{RawFormat: "YYYY.0M"})
+	require.NoError(t, err)
+	month := time.Now().Format("2006.01")
+
+	c1 := testCommit(t, r, "first", time.Now())
+	testTag(t, s, month, c1)
+
+	planned, err := r.CreateReleaseBranch(cv, "", true)
+	require.NoError(t, err)
+	assert.Equal(t, "release/"+month, planned.Branch)
+	_, err = r.Git().Reference(plumbing.NewBranchReferenceName(planned.Branch), false)
+	assert.Error(t, err)
+
+	res, err := r.CreateReleaseBranch(cv, "", false)
+	require.NoError(t, err)
+	assert.Equal(t, c1.String(), res.Hash)
+	_, err = r.CreateReleaseBranch(cv, "", false)
+	assert.ErrorIs(t, err, ErrBranchExists)
+
+	wt, err := r.Git().Worktree()
+	require.NoError(t, err)
+	require.NoError(t, wt.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName(res.Branch)}))
+	base, ok := r.ReleaseBranch(cv)
+	require.True(t, ok)
+	assert.Equal(t, month, base)
+
+	testCommit(t, r, "fix", time.Now())
+	patch, err := r.Tag(TagArgs{CV: cv})
+	require.NoError(t, err)
+	assert.Equal(t, month+".1", patch.Tag)
+	testCommit(t, r, "another fix", time.Now())
+	patch, err = r.Tag(TagArgs{CV: cv})
+	require.NoError(t, err)
+	assert.Equal(t, month+".2", patch.Tag)
+	latest, err := r.Latest(r.ReleaseRegex(cv), false)
+	require.NoError(t, err)
+	assert.Equal(t, month+".2", latest.Tag())
+
+	// Patch tags are listed in their place on the main line but do not hold it back.
+	require.NoError(t, wt.Checkout(&git.CheckoutOptions{Branch: plumbing.Master}))
+	latest, err = r.Latest(r.ReleaseRegex(cv), false)
+	require.NoError(t, err)
+	assert.Equal(t, month+".2", latest.Tag())
+	testCommit(t, r, "feature", time.Now())
+	_, err = r.Tag(TagArgs{CV: cv, Tag: month + "-1"})
+	assert.NoError(t, err)
+	latest, err = r.Latest(r.ReleaseRegex(cv), false)
+	require.NoError(t, err)
+	assert.Equal(t, month+"-1", latest.Tag())
+}
+
+func TestRepoHotfix(t *testing.T) {
+	r, s := newTestRepo(t)
+	f, err := NewFormat("YYYY.0M.0D")
+	require.NoError(t, err)
+	cv := &CalVer{Format: f}
+	day := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+
+	base := testCommit(t, r, "release", day)
+	testTag(t, s, "2024.03.15", base)
+	testTag(t, s, "2024.03.16", testCommit(t, r, "next release", day.Add(24*time.Hour), base))
+	fix := testCommit(t, r, "fix", day.Add(48*time.Hour), base)
+
+	res, err := r.Hotfix(TagArgs{CV: cv, Hash: fix.String()}, "2024.03.15")
+	require.NoError(t, err)
+	assert.Equal(t, "2024.03.15.1", res.Tag)
+	assert.Equal(t, -1, CompareVersions(res.Tag, "2024.03.16"))
+
+	v, err := r.HotfixVersion(f, "2024.03.15.1")
+	require.NoError(t, err)
+	assert.Equal(t, "2024.03.15.2", v)
+	v, err = r.HotfixVersion(f, "2024.03.16-2")
+	require.NoError(t, err)
+	assert.Equal(t, "2024.03.16-2.1", v)
+	assert.Equal(t, -1, CompareVersions(v, "2024.03.16-10"))
+
+	// Same-day releases made after the base stay newer than its hotfixes.
+	testTag(t, s, "2024.03.15-1", testCommit(t, r, "same day", day.Add(time.Hour), base))
+	latest, err := r.Latest(cv.PatchRegex(), false)
+	require.NoError(t, err)
+	assert.Equal(t, "2024.03.16", latest.Tag())
+	res, err = r.Hotfix(TagArgs{CV: cv, Hash: fix.String()}, "2024.03.15.1")
+	require.NoError(t, err)
+	groups, err := r.List(cv.PatchRegex(), 4, false)
+	require.NoError(t, err)
+	tags := make([]string, 0, len(groups))
+	for _, g := range groups {
+		tags = append(tags, g.Tag())
+	}
+	assert.Equal(t, []string{"2024.03.16", "2024.03.15-1", "2024.03.15.2", "2024.03.15.1"}, tags)
+
+	_, err = r.Hotfix(TagArgs{CV: cv, Hash: fix.String()}, "2024.03.16")
+	assert.Error(t, err)
+	_, err = r.Hotfix(TagArgs{CV: cv}, "2023.01.01")
+	assert.ErrorIs(t, err, ErrTagNotFound)
+}
+
+func TestRepoHotfixReleases(t *testing.T) {
+	r, s := newTestRepo(t)
+	f, err := NewFormat("YYYY.0M.0D")
+	require.NoError(t, err)
+	cv := &CalVer{Format: f}
+	day := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+
+	base := testCommit(t, r, "release", day)
+	testTag(t, s, "2024.03.15", base)
+	fix := testCommit(t, r, "fix", day.Add(time.Hour), base)
+	_, err = r.Hotfix(TagArgs{CV: cv, Hash: fix.String()}, "2024.03.15")
+	require.NoError(t, err)
+	feature := testCommit(t, r, "feature", day.Add(24*time.Hour), base)
+	merge := testCommit(t, r, "merge fix", day.Add(48*time.Hour), feature, fix)
+	testTag(t, s, "2024.05.01", merge)
+
+	// The hotfix that shipped the fix comes first, and is listed between its base and the next release.
+	groups, err := r.Contains(r.ReleaseRegex(cv), fix.String())
+	require.NoError(t, err)
+	require.Len(t, groups, 2)
+	assert.Equal(t, "2024.03.15.1", groups[0].Tag())
+	assert.Equal(t, "2024.05.01", groups[1].Tag())
+	groups, err = r.List(r.ReleaseRegex(cv), 5, false)
+	require.NoError(t, err)
+	require.Len(t, groups, 3)
+	assert.Equal(t, "2024.03.15.1", groups[1].Tag())
+
+	d, err := r.Describe(r.ReleaseRegex(cv), fix.String(), false)
+	require.NoError(t, err)
+	assert.Equal(t, "2024.03.15.1", d.String())
+
+	// Day tags are not read as patches of a shorter format unless their base was released.
+	month, err := NewFormat("YYYY.0M")
+	require.NoError(t, err)
+	testTag(t, s, "2024.04", merge)
+	latest, err := r.Latest(r.ReleaseRegex(&CalVer{Format: month}), false)
+	require.NoError(t, err)
+	assert.Equal(t, "2024.04", latest.Tag())
+}
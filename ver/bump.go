@@ -0,0 +1,412 @@
+package ver
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-git/go-billy/v5/util"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrVersionNotFound is returned when a version file has no value at a configured key or pattern.
+var ErrVersionNotFound = errors.New("version not found")
+
+// VersionFile is a project file the version is written into, read from a [calver-file "<path>"] section of the
+// git config, eg.
+//
+//	[calver-file "package.json"]
+//		json = version
+//	[calver-file "charts/app/Chart.yaml"]
+//		yaml = version
+//		yaml = appVersion
+//	[calver-file "pyproject.toml"]
+//		toml = project.version
+//	[calver-file "main.go"]
+//		regex = "Version = \"(.*)\""
+//	[calver-file "VERSION"]
+//
+// A file without keys or patterns holds only the version.
+type VersionFile struct {
+	// Path is relative to the root of the working tree.
+	Path string
+	// JSON, YAML and TOML are dotted keys of string values, eg. project.version. JSON and YAML keys index
+	// arrays by number.
+	JSON []string
+	YAML []string
+	TOML []string
+	// Regex patterns have their first capture group, or else the whole match, replaced by the version.
+	Regex []string
+}
+
+// Bump returns data with the version written at every key and pattern of the file. Everything else, including
+// formatting, is kept.
+func (f VersionFile) Bump(data []byte, version string) ([]byte, error) {
+	if f.isPlain() {
+		return []byte(version + "\n"), nil
+	}
+
+	var err error
+	for _, key := range f.JSON {
+		if data, err = bumpJSON(data, key, version); err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Path, err)
+		}
+	}
+	for _, key := range f.YAML {
+		if data, err = bumpYAML(data, key, version); err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Path, err)
+		}
+	}
+	for _, key := range f.TOML {
+		if data, err = bumpTOML(data, key, version); err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Path, err)
+		}
+	}
+	for _, expr := range f.Regex {
+		if data, err = bumpRegex(data, expr, version); err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Path, err)
+		}
+	}
+	return data, nil
+}
+
+// VersionFiles returns the version files in the order they appear in the config.
+func (r *Repo) VersionFiles() ([]VersionFile, error) {
+	conf, err := r.git.Config()
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve config: %w", err)
+	}
+	if !conf.Raw.HasSection("calver-file") {
+		return nil, nil
+	}
+
+	files := make([]VersionFile, 0)
+	for _, sub := range conf.Raw.Section("calver-file").Subsections {
+		files = append(files, VersionFile{
+			Path:  sub.Name,
+			JSON:  sub.Options.GetAll("json"),
+			YAML:  sub.Options.GetAll("yaml"),
+			TOML:  sub.Options.GetAll("toml"),
+			Regex: sub.Options.GetAll("regex"),
+		})
+	}
+	return files, nil
+}
+
+// BumpFiles writes version into the version files of the working tree and returns the paths that changed.
+// A component prefix such as api/ is dropped, as files hold the bare version. With dryRun set, nothing is
+// written.
+func (r *Repo) BumpFiles(files []VersionFile, version string, dryRun bool) ([]string, error) {
+	_, version = SplitComponent(version)
+	wt, err := r.git.Worktree()
+	if err != nil {
+		return nil, fmt.Errorf("could not open worktree: %w", err)
+	}
+
+	changed := make([]string, 0, len(files))
+	for _, f := range files {
+		data, err := util.ReadFile(wt.Filesystem, f.Path)
+		if err != nil && !(errors.Is(err, os.ErrNotExist) && f.isPlain()) {
+			return changed, fmt.Errorf("could not read %s: %w", f.Path, err)
+		}
+		bumped, err := f.Bump(data, version)
+		if err != nil {
+			return changed, err
+		}
+		if bytes.Equal(data, bumped) {
+			continue
+		}
+
+		changed = append(changed, f.Path)
+		if dryRun {
+			continue
+		}
+		if err := util.WriteFile(wt.Filesystem, f.Path, bumped, 0644); err != nil {
+			return changed, fmt.Errorf("could not write %s: %w", f.Path, err)
+		}
+	}
+	return changed, nil
+}
+
+// CommitFiles commits the given paths of the working tree on HEAD, as the author configured for the
+// repository, and returns the new commit.
+func (r *Repo) CommitFiles(paths []string, msg string) (plumbing.Hash, error) {
+	wt, err := r.git.Worktree()
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("could not open worktree: %w", err)
+	}
+	for _, p := range paths {
+		if _, err := wt.Add(p); err != nil {
+			return plumbing.ZeroHash, fmt.Errorf("could not stage %s: %w", p, err)
+		}
+	}
+	h, err := wt.Commit(msg, &git.CommitOptions{})
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("could not commit: %w", err)
+	}
+	return h, nil
+}
+
+func (f VersionFile) isPlain() bool {
+	return len(f.JSON)+len(f.YAML)+len(f.TOML)+len(f.Regex) == 0
+}
+
+// bumpJSON replaces the string at the dotted key by walking the tokens of data, so the rest of the document
+// is left byte for byte.
+func bumpJSON(data []byte, key, version string) ([]byte, error) {
+	type frame struct {
+		object  bool
+		wantKey bool
+		key     string
+		index   int
+	}
+	want := strings.Split(key, ".")
+	stack := make([]*frame, 0)
+
+	at := func() bool {
+		if len(stack) != len(want) {
+			return false
+		}
+		for i, fr := range stack {
+			elem := fr.key
+			if !fr.object {
+				elem = strconv.Itoa(fr.index)
+			}
+			if elem != want[i] {
+				return false
+			}
+		}
+		return true
+	}
+	next := func() {
+		if len(stack) == 0 {
+			return
+		}
+		if top := stack[len(stack)-1]; top.object {
+			top.wantKey = true
+		} else {
+			top.index++
+		}
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	for {
+		start := dec.InputOffset()
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+
+		if len(stack) > 0 && stack[len(stack)-1].object && stack[len(stack)-1].wantKey {
+			if k, ok := tok.(string); ok {
+				stack[len(stack)-1].key = k
+				stack[len(stack)-1].wantKey = false
+				continue
+			}
+		}
+
+		switch tok {
+		case json.Delim('{'):
+			stack = append(stack, &frame{object: true, wantKey: true})
+			continue
+		case json.Delim('['):
+			stack = append(stack, &frame{})
+			continue
+		case json.Delim('}'), json.Delim(']'):
+			stack = stack[:len(stack)-1]
+			next()
+			continue
+		}
+
+		if at() {
+			if _, ok := tok.(string); !ok {
+				return nil, fmt.Errorf("%s is not a string", key)
+			}
+			// The token may be preceded by a separator and whitespace, never by a quote.
+			start += int64(bytes.IndexByte(data[start:dec.InputOffset()], '"'))
+			quoted, _ := json.Marshal(version)
+			return splice(data, int(start), int(dec.InputOffset()), quoted), nil
+		}
+		next()
+	}
+	return nil, fmt.Errorf("%w at %s", ErrVersionNotFound, key)
+}
+
+// bumpYAML replaces the scalar at the dotted key in place, keeping its quoting. A plain scalar is quoted when
+// the version would otherwise read as a number.
+func bumpYAML(data []byte, key, version string) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid YAML: %w", err)
+	}
+	n := &doc
+	if n.Kind == yaml.DocumentNode && len(n.Content) > 0 {
+		n = n.Content[0]
+	}
+	for _, k := range strings.Split(key, ".") {
+		var found *yaml.Node
+		switch n.Kind {
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(n.Content); i += 2 {
+				if n.Content[i].Value == k {
+					found = n.Content[i+1]
+				}
+			}
+		case yaml.SequenceNode:
+			if i, err := strconv.Atoi(k); err == nil && i >= 0 && i < len(n.Content) {
+				found = n.Content[i]
+			}
+		}
+		if found == nil {
+			return nil, fmt.Errorf("%w at %s", ErrVersionNotFound, key)
+		}
+		n = found
+	}
+	if n.Kind != yaml.ScalarNode {
+		return nil, fmt.Errorf("%s is not a scalar", key)
+	}
+
+	start := lineOffset(data, n.Line) + n.Column - 1
+	var end int
+	var replacement string
+	switch n.Style {
+	case yaml.DoubleQuotedStyle:
+		end = closingQuote(data, start, '"')
+		replacement = `"` + version + `"`
+	case yaml.SingleQuotedStyle:
+		end = closingQuote(data, start, '\'')
+		replacement = `'` + version + `'`
+	case 0:
+		end = start + len(n.Value)
+		replacement = version
+		var v any
+		if err := yaml.Unmarshal([]byte(version), &v); err != nil {
+			replacement = `"` + version + `"`
+		} else if _, ok := v.(string); !ok {
+			replacement = `"` + version + `"`
+		}
+	default:
+		return nil, fmt.Errorf("%s is a block scalar, which is not supported", key)
+	}
+	if end < 0 || end > len(data) {
+		return nil, fmt.Errorf("could not locate %s", key)
+	}
+	return splice(data, start, end, []byte(replacement)), nil
+}
+
+var tomlHeader = regexp.MustCompile(`^\s*\[\[?\s*([^\]]*?)\s*\]\]?\s*(#.*)?$`)
+
+// bumpTOML replaces the string at the dotted key, which may sit under a table header, as a dotted key, or
+// both, eg. project.version under [project]. It works line by line, so multi-line strings and inline tables
+// are not supported.
+func bumpTOML(data []byte, key, version string) ([]byte, error) {
+	parts := strings.Split(key, ".")
+	lines := strings.SplitAfter(string(data), "\n")
+
+	// Longer table names win, so [tool.poetry] is preferred to a tool.poetry.version key at the top level.
+	for i := len(parts) - 1; i >= 0; i-- {
+		table := strings.Join(parts[:i], ".")
+		quoted := make([]string, 0, len(parts)-i)
+		for _, p := range parts[i:] {
+			quoted = append(quoted, `["']?`+regexp.QuoteMeta(p)+`["']?`)
+		}
+		re := regexp.MustCompile(`^(\s*` + strings.Join(quoted, `\s*\.\s*`) + `\s*=\s*)("[^"]*"|'[^']*')`)
+
+		current := ""
+		for n, line := range lines {
+			if m := tomlHeader.FindStringSubmatch(line); m != nil {
+				current = tomlTableName(m[1])
+				continue
+			}
+			if current != table {
+				continue
+			}
+			if m := re.FindStringSubmatchIndex(line); m != nil {
+				q := line[m[4] : m[4]+1]
+				lines[n] = line[:m[4]] + q + version + q + line[m[5]:]
+				return []byte(strings.Join(lines, "")), nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("%w at %s", ErrVersionNotFound, key)
+}
+
+func tomlTableName(header string) string {
+	parts := strings.Split(header, ".")
+	for i, p := range parts {
+		parts[i] = strings.Trim(strings.TrimSpace(p), `"'`)
+	}
+	return strings.Join(parts, ".")
+}
+
+// bumpRegex replaces the first capture group of every match of expr, or the whole match when expr has no
+// groups.
+func bumpRegex(data []byte, expr, version string) ([]byte, error) {
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regex '%s': %w", expr, err)
+	}
+	matches := re.FindAllSubmatchIndex(data, -1)
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("%w matching '%s'", ErrVersionNotFound, expr)
+	}
+
+	out := make([]byte, 0, len(data))
+	last := 0
+	for _, m := range matches {
+		start, end := m[0], m[1]
+		if len(m) > 2 && m[2] >= 0 {
+			start, end = m[2], m[3]
+		}
+		out = append(out, data[last:start]...)
+		out = append(out, version...)
+		last = end
+	}
+	return append(out, data[last:]...), nil
+}
+
+func splice(data []byte, start, end int, replacement []byte) []byte {
+	out := make([]byte, 0, len(data)-(end-start)+len(replacement))
+	out = append(out, data[:start]...)
+	out = append(out, replacement...)
+	return append(out, data[end:]...)
+}
+
+// lineOffset returns the byte offset of the 1-based line in data.
+func lineOffset(data []byte, line int) int {
+	offset := 0
+	for i := 1; i < line; i++ {
+		j := bytes.IndexByte(data[offset:], '\n')
+		if j < 0 {
+			return len(data)
+		}
+		offset += j + 1
+	}
+	return offset
+}
+
+// closingQuote returns the offset just past the quoted scalar opening at start, or -1.
+func closingQuote(data []byte, start int, quote byte) int {
+	for i := start + 1; i < len(data); i++ {
+		switch {
+		case quote == '"' && data[i] == '\\':
+			i++
+		case data[i] == quote && quote == '\'' && i+1 < len(data) && data[i+1] == '\'':
+			i++
+		case data[i] == quote:
+			return i + 1
+		}
+	}
+	return -1
+}
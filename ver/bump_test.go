@@ -0,0 +1,119 @@
+package ver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-git/go-billy/v5/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersionFileBump(t *testing.T) {
+	tests := []struct {
+		name string
+		file VersionFile
+		in   string
+		out  string
+	}{
+		{
+			name: "plain",
+			file: VersionFile{},
+			in:   "1.0.0\n",
+			out:  "2024.03.15\n",
+		},
+		{
+			name: "json",
+			file: VersionFile{JSON: []string{"version", "deps.1.version"}},
+			in:   "{\n  \"scripts\": {\"version\": \"x\"},\n  \"version\" : \"1.0.0\",\n  \"deps\": [{}, {\"version\": \"1\"}]\n}\n",
+			out:  "{\n  \"scripts\": {\"version\": \"x\"},\n  \"version\" : \"2024.03.15\",\n  \"deps\": [{}, {\"version\": \"2024.03.15\"}]\n}\n",
+		},
+		{
+			name: "yaml",
+			file: VersionFile{YAML: []string{"version", "image.tag"}},
+			in:   "version: 0.1.0 # chart\nimage:\n  tag: 'dev'\n",
+			out:  "version: 2024.03.15 # chart\nimage:\n  tag: '2024.03.15'\n",
+		},
+		{
+			name: "toml",
+			file: VersionFile{TOML: []string{"project.version", "version"}},
+			in:   "version = \"0\"\n[tool]\nversion = \"1\"\n[project]\nversion = '0.1.0' # keep\n",
+			out:  "version = \"2024.03.15\"\n[tool]\nversion = \"1\"\n[project]\nversion = '2024.03.15' # keep\n",
+		},
+		{
+			name: "regex",
+			file: VersionFile{Regex: []string{`Version = "(.*)"`}},
+			in:   "const Version = \"dev\"\n",
+			out:  "const Version = \"2024.03.15\"\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := tt.file.Bump([]byte(tt.in), "2024.03.15")
+			require.NoError(t, err)
+			assert.Equal(t, tt.out, string(out))
+		})
+	}
+
+	// A plain YAML scalar is quoted when the version would read as a number.
+	out, err := VersionFile{YAML: []string{"version"}}.Bump([]byte("version: x\n"), "2024.03")
+	require.NoError(t, err)
+	assert.Equal(t, "version: \"2024.03\"\n", string(out))
+
+	_, err = VersionFile{JSON: []string{"missing"}}.Bump([]byte(`{"version": "1"}`), "2024.03.15")
+	assert.ErrorIs(t, err, ErrVersionNotFound)
+}
+
+func TestRepoBumpFiles(t *testing.T) {
+	r, _ := newTestRepo(t)
+	wt, err := r.Git().Worktree()
+	require.NoError(t, err)
+	require.NoError(t, util.WriteFile(wt.Filesystem, "package.json", []byte(`{"version": "1.0.0"}`), 0644))
+	testCommit(t, r, "first", time.Now())
+
+	cfg, err := r.Git().Config()
+	require.NoError(t, err)
+	cfg.User.Name, cfg.User.Email = "dev", "dev@example.com"
+	cfg.Raw.Section("calver-file").Subsection("package.json").SetOption("json", "version")
+	cfg.Raw.Section("calver-file").Subsection("VERSION")
+	require.NoError(t, r.Git().SetConfig(cfg))
+
+	files, err := r.VersionFiles()
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+
+	changed, err := r.BumpFiles(files, "2024.03.15", true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"package.json", "VERSION"}, changed)
+	data, err := util.ReadFile(wt.Filesystem, "package.json")
+	require.NoError(t, err)
+	assert.Equal(t, `{"version": "1.0.0"}`, string(data))
+
+	changed, err = r.BumpFiles(files, "2024.03.15", false)
+	require.NoError(t, err)
+	h, err := r.CommitFiles(changed, "chore(release): 2024.03.15")
+	require.NoError(t, err)
+
+	co, err := r.ResolveCommit("HEAD")
+	require.NoError(t, err)
+	assert.Equal(t, h, co.Hash)
+	f, err := co.File("VERSION")
+	require.NoError(t, err)
+	contents, err := f.Contents()
+	require.NoError(t, err)
+	assert.Equal(t, "2024.03.15\n", contents)
+
+	// Component releases write the version without the component prefix of their tag.
+	cv, err := r.Next(CalVerArgs{RawFormat: "YYYY.0M.0D", Component: "api"})
+	require.NoError(t, err)
+	tag, _, err := r.NextVersion(cv)
+	require.NoError(t, err)
+	_, version := SplitComponent(tag)
+	assert.Equal(t, "api/"+version, tag)
+	changed, err = r.BumpFiles(files, tag, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"package.json", "VERSION"}, changed)
+	data, err = util.ReadFile(wt.Filesystem, "package.json")
+	require.NoError(t, err)
+	assert.Equal(t, `{"version": "`+version+`"}`, string(data))
+}
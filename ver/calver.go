@@ -9,6 +9,8 @@ import (
 )
 
 type CalVer struct {
+	// Component prefixes tags as component/version, giving each component of a monorepo its own sequence.
+	Component     string
 	Format        *Format
 	Minor         uint
 	Micro         uint
@@ -50,24 +52,63 @@ func (f *Format) Version(t time.Time) string {
 	return strings.Join(bits, ".")
 }
 
+// Regex matches the tags of the format. A modifier may contain dots, as in 2024.03.15-dev.1 or the hotfix
+// 2024.03.15-2.1, but the calendar part must match the format exactly.
 func (c *CalVer) Regex() *regexp.Regexp {
+	return c.regex("")
+}
+
+// PatchRegex is Regex that also matches the patch tags made on release branches and by hotfixes, such as
+// 2024.03.1 for the format YYYY.0M. It is ambiguous for formats shorter than the tags around them, since the
+// day in 2024.03.15 reads as a patch of 2024.03, so it is only used where patch tags are expected.
+func (c *CalVer) PatchRegex() *regexp.Regexp {
+	return c.regex(`(\.[0-9]+)?`)
+}
+
+func (c *CalVer) regex(patch string) *regexp.Regexp {
 	mod := "0,1"
 	if c.AutoIncrement || c.Modifier != "" {
 		mod = "1"
 	}
+	prefix := ""
+	if c.Component != "" {
+		prefix = regexp.QuoteMeta(c.Component + "/")
+	}
+	modifier := `-\w+(\.\w+)*`
 
 	if c.Format.Minor == segmentEmpty {
-		r, _ := regexp.Compile(fmt.Sprintf(`^%s(-(\w+)){%s}$`, c.Format.Major.Regex(), mod))
+		r, _ := regexp.Compile(fmt.Sprintf(`^%s%s%s(%s){%s}$`, prefix, c.Format.Major.Regex(), patch, modifier, mod))
 		return r
 	}
 	if c.Format.Micro == segmentEmpty {
-		r, _ := regexp.Compile(fmt.Sprintf(`^%s\.%s(-\w+){%s}$`, c.Format.Major.Regex(), c.Format.Minor.Regex(), mod))
+		r, _ := regexp.Compile(fmt.Sprintf(`^%s%s\.%s%s(%s){%s}$`, prefix, c.Format.Major.Regex(), c.Format.Minor.Regex(), patch, modifier, mod))
 		return r
 	}
-	r, _ := regexp.Compile(fmt.Sprintf(`^%s\.%s\.%s(-\w+){%s}$`, c.Format.Major.Regex(), c.Format.Minor.Regex(), c.Format.Micro.Regex(), mod))
+	r, _ := regexp.Compile(fmt.Sprintf(`^%s%s\.%s\.%s%s(%s){%s}$`, prefix, c.Format.Major.Regex(), c.Format.Minor.Regex(), c.Format.Micro.Regex(), patch, modifier, mod))
 	return r
 }
 
+// SplitComponent splits a tag such as api/2024.03.15-3 into its component and version. Tags without a
+// component return an empty component.
+func SplitComponent(tag string) (string, string) {
+	i := strings.LastIndex(tag, "/")
+	if i < 0 {
+		return "", tag
+	}
+	return tag[:i], tag[i+1:]
+}
+
+// Segments returns the number of segments in versions of the format, counting MINOR and MICRO.
+func (f *Format) Segments() int {
+	n := 0
+	for _, s := range []segment{f.Major, f.Minor, f.Micro} {
+		if s != segmentEmpty {
+			n++
+		}
+	}
+	return n
+}
+
 func (f *Format) NeedsMinor() bool {
 	return f.Minor == segmentMinor
 }
@@ -318,6 +359,18 @@ type CalVerArgs struct {
 	DryRun        bool
 	AutoIncrement bool
 	Hash          string
+	// RemoteURL reads existing tags from a remote instead of the local repository.
+	RemoteURL string
+	// Component prefixes tags as component/version.
+	Component string
+	// Branch selects the branch policy to apply, instead of the branch checked out at HEAD. CI jobs on a
+	// detached HEAD set it to the branch being built.
+	Branch string
+	// IgnoreBranchPolicy skips the [calver-branch] policies.
+	IgnoreBranchPolicy bool
+	// AnyBranch applies the modifier and auto-increment of the branch policy, but does not refuse branches
+	// where tagging is not allowed. It is for callers that only compute versions.
+	AnyBranch bool
 }
 
 func (c *CalVerArgs) String() string {
@@ -326,6 +379,7 @@ func (c *CalVerArgs) String() string {
 
 func NewCalVer(a CalVerArgs) (*CalVer, error) {
 	c := &CalVer{
+		Component:     a.Component,
 		Format:        a.Format,
 		Modifier:      a.Modifier,
 		AutoIncrement: a.AutoIncrement,
@@ -351,17 +405,39 @@ func NewCalVer(a CalVerArgs) (*CalVer, error) {
 	return c, nil
 }
 
+// NextCalVer builds the CalVer for the next release, resolving the auto-increment against RepoPath or, when
+// a.RemoteURL is set, against the remote's tags. The branch policy of RepoPath is applied first.
 func NextCalVer(a CalVerArgs) (*CalVer, error) {
+	if r, err := Open(RepoPath); err == nil {
+		if err := r.applyBranchPolicy(&a); err != nil {
+			return nil, err
+		}
+	}
+	if a.RemoteURL != "" {
+		return nextCalVer(a, func(c *CalVer) (int, error) {
+			return GetLatestRemoteAutoInc(c, a.RemoteURL)
+		})
+	}
+	return nextCalVer(a, func(c *CalVer) (int, error) {
+		nextInc, err := GetLatestAutoInc(c)
+		if err != nil {
+			return 1, nil
+		}
+		return nextInc, nil
+	})
+}
+
+func nextCalVer(a CalVerArgs, autoInc func(*CalVer) (int, error)) (*CalVer, error) {
 	c, err := NewCalVer(a)
 	if err != nil {
 		return nil, err
 	}
 	if c.AutoIncrement {
-		nextInc, err := GetLatestAutoInc(c)
+		nextInc, err := autoInc(c)
 		if err != nil {
-			nextInc = 1
+			return nil, err
 		}
-		c.Modifier = c.Modifier + strconv.Itoa(nextInc)
+		c.Increment = uint(nextInc)
 	}
 
 	return c, nil
@@ -383,8 +459,15 @@ func (c *CalVer) Version(t time.Time) (string, error) {
 		ver = fmt.Sprintf("%s.%d", ver, c.Micro)
 	}
 
-	if c.Modifier != "" {
-		ver = fmt.Sprintf("%s-%s", ver, c.Modifier)
+	mod := c.Modifier
+	if c.Increment > 0 {
+		mod += strconv.FormatUint(uint64(c.Increment), 10)
+	}
+	if mod != "" {
+		ver = fmt.Sprintf("%s-%s", ver, mod)
+	}
+	if c.Component != "" {
+		ver = c.Component + "/" + ver
 	}
 
 	return ver, nil
@@ -3,6 +3,7 @@ package ver
 import (
 	"fmt"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"testing"
 	"time"
 )
@@ -292,3 +293,94 @@ func TestCalVerVersion(t *testing.T) {
 		})
 	}
 }
+
+func TestCalVerIncrement(t *testing.T) {
+	tests := []struct {
+		modifier  string
+		increment uint
+		out       string
+	}{
+		{modifier: "", increment: 0, out: "2024.01"},
+		{modifier: "", increment: 3, out: "2024.01-3"},
+		{modifier: "RC", increment: 0, out: "2024.01-RC"},
+		{modifier: "RC", increment: 12, out: "2024.01-RC12"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.out, func(t *testing.T) {
+			cv, err := NewCalVer(CalVerArgs{RawFormat: "YYYY.0M", Modifier: test.modifier, AutoIncrement: true})
+			assert.NoError(t, err)
+			cv.Increment = test.increment
+
+			out, err := cv.Version(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+			assert.NoError(t, err)
+			assert.Equal(t, test.out, out)
+		})
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"2024.10", "2024.9", 1},
+		{"2024.03.15", "2024.03.15", 0},
+		{"2024.03.15-1", "2024.03.15", 1},
+		{"2024.03.15-2", "2024.03.15-10", -1},
+		{"2024.03.15-RC10", "2024.03.15-RC2", 1},
+		{"2024.03.15-RC1", "2024.03.15", -1},
+		{"2024.03.15-dev.1", "2024.03.15", -1},
+		{"2024.03.15-1", "2024.03.15-RC1", 1},
+		{"2024.03.15-dev.2", "2024.03.15-dev.10", -1},
+		{"2024.03.15-dev.3", "2024.03.15-beta.1", -1},
+		{"2024.03.15-beta2", "2024.03.15-RC1", -1},
+		{"2024.03.15-rc1", "2024.03.15-RC1", 0},
+		{"2024.03.15-RC", "2024.03.15-RC1", -1},
+		{"2024.03.15-nightly", "2024.03.15-dev", -1},
+		{"2024.03.15-RC1.1", "2024.03.15-RC1", 1},
+		{"2024.03.15-2.1", "2024.03.15-3", -1},
+		{"2024.03.15.1", "2024.03.15", 1},
+		{"2024.03.15.1", "2024.03.15-1", -1},
+		{"2024.03.15.1", "2024.03.15-RC1", 1},
+		{"2024.03.15.2", "2024.03.15.1", 1},
+		{"24.03.15", "2024.03.15", -1},
+		{"2024.03", "2024.03.01", -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+" "+tt.b, func(t *testing.T) {
+			assert.Equal(t, tt.want, CompareVersions(tt.a, tt.b))
+		})
+	}
+}
+
+func TestCalVerComponent(t *testing.T) {
+	cv, err := NewCalVer(CalVerArgs{RawFormat: "YYYY.0M.0D", Component: "web-app", AutoIncrement: true})
+	assert.NoError(t, err)
+	cv.Increment = 3
+
+	v, err := cv.Version(time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, "web-app/2024.03.15-3", v)
+	assert.True(t, cv.Regex().MatchString(v))
+	assert.False(t, cv.Regex().MatchString("2024.03.15-3"))
+	assert.False(t, cv.Regex().MatchString("api/2024.03.15-3"))
+
+	component, version := SplitComponent(v)
+	assert.Equal(t, "web-app", component)
+	assert.Equal(t, "2024.03.15-3", version)
+}
+
+func TestCalVerRegexPatch(t *testing.T) {
+	cv, err := NewCalVer(CalVerArgs{RawFormat: "YYYY.0M"})
+	require.NoError(t, err)
+
+	// Day tags of a longer format are not releases of YYYY.0M.
+	assert.True(t, cv.Regex().MatchString("2026.10"))
+	assert.True(t, cv.Regex().MatchString("2026.10-dev.1"))
+	assert.False(t, cv.Regex().MatchString("2026.10.19-7"))
+	assert.False(t, cv.Regex().MatchString("2026.10.1"))
+
+	assert.True(t, cv.PatchRegex().MatchString("2026.10.1"))
+	assert.True(t, cv.PatchRegex().MatchString("2026.10-2.1"))
+}
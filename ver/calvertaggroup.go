@@ -30,6 +30,19 @@ func (cvt *CalVerTagGroup) Time() time.Time {
 	return cvt.Commit.Author.When
 }
 
+// Tag returns the group's first tag.
+func (cvt *CalVerTagGroup) Tag() string {
+	if len(cvt.Tags) == 0 {
+		return ""
+	}
+	return cvt.Tags[0]
+}
+
+// Groups returns the changelog grouped by conventional commit type.
+func (cvt *CalVerTagGroup) Groups() []ChangeGroup {
+	return GroupChanges(cvt.ChangeLog)
+}
+
 func (cvt *CalVerTagGroup) printTags() string {
 	result := ""
 	for i, tag := range cvt.Tags {
@@ -49,7 +62,7 @@ func (cvt *CalVerTagGroup) Print(w io.Writer, noColour bool, lean bool) {
 	}
 
 	if lean {
-		_, _ = w.Write([]byte(cvt.LatestTag + "\n"))
+		_, _ = w.Write([]byte(cvt.Tag() + "\n"))
 		return
 	}
 
@@ -60,26 +73,86 @@ func (cvt *CalVerTagGroup) Print(w io.Writer, noColour bool, lean bool) {
 		headline += colour.Green.Sprintf(cvt.printTags())
 	}
 
+	if cvt.Commit == nil {
+		_, _ = w.Write([]byte(fmt.Sprintf("\n%s\n", headline)))
+		return
+	}
+
 	subtitle := fmt.Sprintf("%s - %s", cvt.Commit.Author.Name, pretty.Format(cvt.Time()))
 
 	changeLog := "CHANGELOG:"
 
-	for i, commit := range cvt.ChangeLog {
-		b := colour.Red.Sprint("*")
-		when := colour.Gray.Sprint(commit.Author.When.Format("2006-01-02 15:04"))
-		hash := colour.Yellow.Sprint(commit.Hash.String()[:7])
-		msg := commit.Message
-		who := colour.Cyan.Sprintf("[%s]", commit.Author.Name)
-
-		line := fmt.Sprintf("\t%s %s  %s  %s  %s", b, when, hash, msg, who)
-		line = strings.Replace(line, "\n", "", -1)
-		changeLog += "\n" + line
-		if i > 10 {
-			changeLog += "\n\t..."
-			break
+	if hasConventional(cvt.ChangeLog) {
+		changeLog += cvt.printGroups()
+	} else {
+		for i, commit := range cvt.ChangeLog {
+			changeLog += "\n" + printCommit(commit, commit.Message)
+			if i > 10 {
+				changeLog += "\n\t..."
+				break
+			}
 		}
 	}
 
 	result := fmt.Sprintf("\n%s\n%s\n%s\n", headline, subtitle, changeLog)
 	_, _ = w.Write([]byte(result))
 }
+
+// printGroups renders the changelog grouped by conventional commit type.
+func (cvt *CalVerTagGroup) printGroups() string {
+	result := ""
+	shown := 0
+	for _, group := range GroupChanges(cvt.ChangeLog) {
+		result += "\n  " + colour.Magenta.Sprintf("%s:", group.Title)
+		for _, cc := range group.Commits {
+			msg := cc.Subject
+			if cc.Scope != "" {
+				msg = colour.Bold.Sprintf("%s:", cc.Scope) + " " + msg
+			}
+			result += "\n" + printCommit(cc.Commit, msg)
+			shown++
+			if shown > 11 {
+				return result + "\n\t..."
+			}
+		}
+	}
+	return result
+}
+
+func printCommit(commit *object.Commit, msg string) string {
+	b := colour.Red.Sprint("*")
+	when := colour.Gray.Sprint(commit.Author.When.Format("2006-01-02 15:04"))
+	hash := colour.Yellow.Sprint(commit.Hash.String()[:7])
+	who := colour.Cyan.Sprintf("[%s]", commit.Author.Name)
+
+	line := fmt.Sprintf("\t%s %s  %s  %s  %s", b, when, hash, msg, who)
+	return strings.Replace(line, "\n", "", -1)
+}
+
+// Markdown writes the release as a Markdown section headed by its first tag and date, with the changelog
+// grouped by conventional commit type.
+func (cvt *CalVerTagGroup) Markdown(w io.Writer) {
+	title := cvt.Hash
+	if len(cvt.Tags) > 0 {
+		title = "[" + cvt.Tags[0] + "]"
+	}
+	if !cvt.Time().IsZero() {
+		title += " - " + cvt.Time().Format("2006-01-02")
+	}
+	WriteMarkdownSection(w, title, cvt.ChangeLog)
+}
+
+// WriteMarkdownSection writes a level two heading followed by the commits grouped by conventional commit type.
+func WriteMarkdownSection(w io.Writer, title string, commits []*object.Commit) {
+	_, _ = fmt.Fprintf(w, "## %s\n", title)
+	for _, group := range GroupChanges(commits) {
+		_, _ = fmt.Fprintf(w, "\n### %s\n\n", group.Title)
+		for _, cc := range group.Commits {
+			msg := cc.Subject
+			if cc.Scope != "" {
+				msg = fmt.Sprintf("**%s:** %s", cc.Scope, msg)
+			}
+			_, _ = fmt.Fprintf(w, "- %s (%s)\n", msg, cc.Commit.Hash.String()[:7])
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package ver
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// ChangelogHeader opens a generated changelog document.
+const ChangelogHeader = `# Changelog
+
+All notable changes to this project will be documented in this file.
+
+The format is based on [Keep a Changelog](https://keepachangelog.com/en/1.1.0/),
+and this project uses [Calendar Versioning](https://calver.org/).
+`
+
+const unreleasedName = "Unreleased"
+
+var releaseHeading = regexp.MustCompile(`^## \[([^\]]+)\]`)
+
+// UpdateChangelog merges releases into an existing Keep a Changelog document and returns the result. Releases
+// newer than the newest documented one are inserted above it, newest first, and existing sections are left
+// untouched. The Unreleased section is replaced by the given commits, or dropped when unreleased is nil. An
+// empty document is generated from scratch.
+func UpdateChangelog(existing string, groups []*CalVerTagGroup, unreleased []*object.Commit) string {
+	preamble, sections := splitChangelog(existing)
+
+	documented := make(map[string]bool)
+	kept := make([]string, 0, len(sections))
+	for _, section := range sections {
+		m := releaseHeading.FindStringSubmatch(section)
+		if m != nil && m[1] == unreleasedName {
+			continue
+		}
+		if m != nil {
+			documented[m[1]] = true
+		}
+		kept = append(kept, section)
+	}
+
+	added := make([]string, 0)
+	if unreleased != nil {
+		var b bytes.Buffer
+		WriteMarkdownSection(&b, "["+unreleasedName+"]", unreleased)
+		added = append(added, b.String())
+	}
+	for _, group := range groups {
+		if len(group.Tags) == 0 {
+			continue
+		}
+		if documented[group.Tags[0]] {
+			break
+		}
+		var b bytes.Buffer
+		group.Markdown(&b)
+		added = append(added, b.String())
+	}
+
+	result := preamble
+	for _, section := range append(added, kept...) {
+		result += "\n" + strings.TrimRight(section, "\n") + "\n"
+	}
+	return result
+}
+
+// splitChangelog splits a changelog into the text before the first level two heading and the sections that
+// each start with one. An empty changelog gets the default header.
+func splitChangelog(doc string) (string, []string) {
+	if strings.TrimSpace(doc) == "" {
+		return ChangelogHeader, nil
+	}
+
+	preamble := ""
+	sections := make([]string, 0)
+	for _, line := range strings.SplitAfter(doc, "\n") {
+		if strings.HasPrefix(line, "## ") {
+			sections = append(sections, line)
+			continue
+		}
+		if len(sections) == 0 {
+			preamble += line
+			continue
+		}
+		sections[len(sections)-1] += line
+	}
+	return strings.TrimRight(preamble, "\n") + "\n", sections
+}
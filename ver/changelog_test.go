@@ -0,0 +1,60 @@
+package ver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateChangelog(t *testing.T) {
+	release := func(tag string, day int, msgs ...string) *CalVerTagGroup {
+		commits := make([]*object.Commit, 0)
+		for _, msg := range msgs {
+			commits = append(commits, testObject(msg, 0))
+		}
+		return &CalVerTagGroup{
+			Tags:      []string{tag},
+			Commit:    &object.Commit{Author: object.Signature{When: time.Date(2024, 1, day, 0, 0, 0, 0, time.UTC)}},
+			ChangeLog: commits,
+		}
+	}
+	older := release("2024.01.01", 1, "feat: first")
+	newer := release("2024.01.02", 2, "fix: second")
+	newest := release("2024.01.03", 3, "docs: third")
+
+	doc := UpdateChangelog("", []*CalVerTagGroup{newer, older}, []*object.Commit{})
+	assert.Equal(t, ChangelogHeader+`
+## [Unreleased]
+
+## [2024.01.02] - 2024-01-02
+
+### Fixes
+
+- second (0000000)
+
+## [2024.01.01] - 2024-01-01
+
+### Features
+
+- first (0000000)
+`, doc)
+
+	// Hand edits survive, the Unreleased section is dropped and only releases newer than the newest
+	// documented one are added.
+	edited := "# Releases\n\n## [Unreleased]\n\n- wip\n\n## [2024.01.02] - 2024-01-02\n\nHand written.\n"
+	doc = UpdateChangelog(edited, []*CalVerTagGroup{newest, newer, older}, nil)
+	assert.Equal(t, `# Releases
+
+## [2024.01.03] - 2024-01-03
+
+### Other
+
+- third (0000000)
+
+## [2024.01.02] - 2024-01-02
+
+Hand written.
+`, doc)
+}
@@ -0,0 +1,80 @@
+package ver
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// EnvVar is a named value handed to a CI system.
+type EnvVar struct {
+	Name  string
+	Value string
+}
+
+// Vars returns the variables describing the release for CI systems, in a fixed order: version, major, minor,
+// micro, modifier, hash and short_hash, followed by component for component releases. Segments the version
+// does not have are left empty.
+func (info ReleaseInfo) Vars() []EnvVar {
+	segment := func(i int) string {
+		if i < len(info.Segments) {
+			return strconv.Itoa(info.Segments[i])
+		}
+		return ""
+	}
+	short := info.Hash
+	if len(short) > 7 {
+		short = short[:7]
+	}
+
+	vars := []EnvVar{
+		{Name: "version", Value: info.Version},
+		{Name: "major", Value: segment(0)},
+		{Name: "minor", Value: segment(1)},
+		{Name: "micro", Value: segment(2)},
+		{Name: "modifier", Value: info.Modifier},
+		{Name: "hash", Value: info.Hash},
+		{Name: "short_hash", Value: short},
+	}
+	if info.Component != "" {
+		vars = append(vars, EnvVar{Name: "component", Value: info.Component})
+	}
+	return vars
+}
+
+// WriteGitHubOutput writes the variables as name=value lines, the format GitHub Actions reads from
+// $GITHUB_OUTPUT.
+func WriteGitHubOutput(w io.Writer, vars []EnvVar) error {
+	for _, v := range vars {
+		if _, err := fmt.Fprintf(w, "%s=%s\n", v.Name, v.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteDotenv writes the variables as CALVER_NAME=value lines, the format of GitLab dotenv report artifacts.
+func WriteDotenv(w io.Writer, vars []EnvVar) error {
+	for _, v := range vars {
+		if _, err := fmt.Fprintf(w, "%s=%s\n", envName(v.Name), v.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteExports writes the variables as shell export statements, for use with eval.
+func WriteExports(w io.Writer, vars []EnvVar) error {
+	for _, v := range vars {
+		value := "'" + strings.ReplaceAll(v.Value, "'", `'\''`) + "'"
+		if _, err := fmt.Fprintf(w, "export %s=%s\n", envName(v.Name), value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func envName(name string) string {
+	return "CALVER_" + strings.ToUpper(name)
+}
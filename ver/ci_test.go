@@ -0,0 +1,26 @@
+package ver
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReleaseVars(t *testing.T) {
+	info := NewReleaseInfo("24.03-RC1")
+	info.Hash = "abc1234def5678"
+
+	var gh bytes.Buffer
+	require.NoError(t, WriteGitHubOutput(&gh, info.Vars()))
+	assert.Equal(t, "version=24.03-RC1\nmajor=24\nminor=3\nmicro=\nmodifier=RC1\nhash=abc1234def5678\nshort_hash=abc1234\n", gh.String())
+
+	var env bytes.Buffer
+	require.NoError(t, WriteDotenv(&env, info.Vars()[:1]))
+	assert.Equal(t, "CALVER_VERSION=24.03-RC1\n", env.String())
+
+	var exports bytes.Buffer
+	require.NoError(t, WriteExports(&exports, []EnvVar{{Name: "modifier", Value: "it's"}}))
+	assert.Equal(t, "export CALVER_MODIFIER='it'\\''s'\n", exports.String())
+}
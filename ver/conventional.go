@@ -0,0 +1,111 @@
+package ver
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+const (
+	// GroupBreaking holds commits marked with ! or a BREAKING CHANGE footer, whatever their type.
+	GroupBreaking = "Breaking"
+	// GroupFeatures holds feat commits.
+	GroupFeatures = "Features"
+	// GroupFixes holds fix commits.
+	GroupFixes = "Fixes"
+	// GroupOther holds every other commit, including ones without a conventional header.
+	GroupOther = "Other"
+)
+
+// changeGroupOrder is the order groups are rendered in.
+var changeGroupOrder = []string{GroupBreaking, GroupFeatures, GroupFixes, GroupOther}
+
+var conventionalHeader = regexp.MustCompile(`^(\w+)(?:\(([^)]*)\))?(!)?: (.+)$`)
+
+// ConventionalCommit is a commit message parsed according to https://www.conventionalcommits.org.
+type ConventionalCommit struct {
+	// Type is the lower-cased commit type, eg. feat or fix. It is empty for non-conventional messages.
+	Type     string
+	Scope    string
+	Breaking bool
+	// Subject is the header description, or the first line for non-conventional messages.
+	Subject string
+	Commit  *object.Commit
+}
+
+// ParseConventionalCommit parses a commit message header and footers.
+func ParseConventionalCommit(msg string) ConventionalCommit {
+	lines := strings.Split(strings.TrimSpace(msg), "\n")
+	header := strings.TrimSpace(lines[0])
+
+	cc := ConventionalCommit{Subject: header}
+	m := conventionalHeader.FindStringSubmatch(header)
+	if m != nil {
+		cc.Type = strings.ToLower(m[1])
+		cc.Scope = m[2]
+		cc.Breaking = m[3] == "!"
+		cc.Subject = m[4]
+	}
+
+	for _, line := range lines[1:] {
+		if strings.HasPrefix(line, "BREAKING CHANGE:") || strings.HasPrefix(line, "BREAKING-CHANGE:") {
+			cc.Breaking = true
+		}
+	}
+	return cc
+}
+
+// Conventional reports whether the message had a conventional commit header.
+func (cc ConventionalCommit) Conventional() bool {
+	return cc.Type != ""
+}
+
+// Group returns the changelog group the commit belongs to.
+func (cc ConventionalCommit) Group() string {
+	switch {
+	case cc.Breaking:
+		return GroupBreaking
+	case cc.Type == "feat":
+		return GroupFeatures
+	case cc.Type == "fix":
+		return GroupFixes
+	default:
+		return GroupOther
+	}
+}
+
+// ChangeGroup is a titled set of commits in a changelog.
+type ChangeGroup struct {
+	Title   string
+	Commits []ConventionalCommit
+}
+
+// GroupChanges parses commits and groups them as Breaking, Features, Fixes and Other, in that order.
+// Empty groups are left out and commits keep their order within a group.
+func GroupChanges(commits []*object.Commit) []ChangeGroup {
+	byGroup := make(map[string][]ConventionalCommit)
+	for _, c := range commits {
+		cc := ParseConventionalCommit(c.Message)
+		cc.Commit = c
+		byGroup[cc.Group()] = append(byGroup[cc.Group()], cc)
+	}
+
+	groups := make([]ChangeGroup, 0)
+	for _, title := range changeGroupOrder {
+		if len(byGroup[title]) > 0 {
+			groups = append(groups, ChangeGroup{Title: title, Commits: byGroup[title]})
+		}
+	}
+	return groups
+}
+
+// hasConventional reports whether any commit uses a conventional commit header.
+func hasConventional(commits []*object.Commit) bool {
+	for _, c := range commits {
+		if ParseConventionalCommit(c.Message).Conventional() {
+			return true
+		}
+	}
+	return false
+}
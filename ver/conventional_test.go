@@ -0,0 +1,106 @@
+package ver
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseConventionalCommit(t *testing.T) {
+	tests := []struct {
+		msg string
+		out ConventionalCommit
+	}{
+		{
+			msg: "feat: add describe command",
+			out: ConventionalCommit{Type: "feat", Subject: "add describe command"},
+		},
+		{
+			msg: "fix(api): handle empty tag list\n",
+			out: ConventionalCommit{Type: "fix", Scope: "api", Subject: "handle empty tag list"},
+		},
+		{
+			msg: "refactor(ver)!: drop package level functions",
+			out: ConventionalCommit{Type: "refactor", Scope: "ver", Breaking: true, Subject: "drop package level functions"},
+		},
+		{
+			msg: "Feat: capitalised type",
+			out: ConventionalCommit{Type: "feat", Subject: "capitalised type"},
+		},
+		{
+			msg: "chore: bump deps\n\nBREAKING CHANGE: requires go 1.22",
+			out: ConventionalCommit{Type: "chore", Breaking: true, Subject: "bump deps"},
+		},
+		{
+			msg: "fix: typo\n\nBREAKING-CHANGE: renamed flag",
+			out: ConventionalCommit{Type: "fix", Breaking: true, Subject: "typo"},
+		},
+		{
+			msg: "Merge branch 'main' into feature",
+			out: ConventionalCommit{Subject: "Merge branch 'main' into feature"},
+		},
+		{
+			msg: "feat:missing space",
+			out: ConventionalCommit{Subject: "feat:missing space"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			assert.Equal(t, test.out, ParseConventionalCommit(test.msg))
+		})
+	}
+}
+
+func TestGroupChanges(t *testing.T) {
+	commits := []*object.Commit{
+		testObject("fix: second fix", 1),
+		testObject("feat(cli): new flag", 2),
+		testObject("docs: readme", 3),
+		testObject("feat!: removed flag", 4),
+		testObject("fix: first fix", 5),
+	}
+
+	groups := GroupChanges(commits)
+	titles := make([]string, 0)
+	for _, g := range groups {
+		titles = append(titles, g.Title)
+	}
+	assert.Equal(t, []string{GroupBreaking, GroupFeatures, GroupFixes, GroupOther}, titles)
+	assert.Equal(t, "second fix", groups[2].Commits[0].Subject)
+	assert.Equal(t, "first fix", groups[2].Commits[1].Subject)
+
+	var b bytes.Buffer
+	WriteMarkdownSection(&b, "[2024.01.02] - 2024-01-02", commits)
+	assert.Equal(t, `## [2024.01.02] - 2024-01-02
+
+### Breaking
+
+- removed flag (0000000)
+
+### Features
+
+- **cli:** new flag (0000000)
+
+### Fixes
+
+- second fix (0000000)
+- first fix (0000000)
+
+### Other
+
+- readme (0000000)
+`, b.String())
+}
+
+func testObject(msg string, minutes int) *object.Commit {
+	return &object.Commit{
+		Hash:    plumbing.ZeroHash,
+		Message: msg,
+		Author:  object.Signature{Name: "dev", When: time.Date(2024, 1, 2, 0, minutes, 0, 0, time.UTC)},
+	}
+}
@@ -0,0 +1,127 @@
+package ver
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// Description places a commit relative to its nearest calver tag, like git describe.
+type Description struct {
+	// Tag is the nearest ancestor tag.
+	Tag string
+	// Distance is the number of commits between Tag and the described commit.
+	Distance int
+	// Hash is the full hash of the described commit.
+	Hash string
+	// Dirty is set when the working tree has uncommitted changes.
+	Dirty bool
+}
+
+// String renders the description as git describe does, eg. 2024.03.15-7-gabc1234-dirty. A tagged, clean commit
+// is rendered as the tag alone.
+func (d *Description) String() string {
+	s := d.Tag
+	if d.Distance > 0 {
+		s = fmt.Sprintf("%s-%d-g%s", s, d.Distance, d.Hash[:7])
+	}
+	if d.Dirty {
+		s += "-dirty"
+	}
+	return s
+}
+
+// Describe finds the calver tag matching reg nearest to rev (HEAD when empty), walking its history breadth-first,
+// and counts the commits made since. With dirty set, uncommitted changes in the working tree are reported too.
+func (r *Repo) Describe(reg *regexp.Regexp, rev string, dirty bool) (*Description, error) {
+	co, err := r.ResolveCommit(rev)
+	if err != nil {
+		return nil, err
+	}
+
+	refs, err := r.tags.Tags()
+	if err != nil {
+		return nil, fmt.Errorf("could not find tags: %w", err)
+	}
+	tagged := make(map[plumbing.Hash][]string)
+	for _, ref := range refs {
+		short := ref.Name().Short()
+		if !reg.MatchString(short) {
+			continue
+		}
+		if c, err := r.commitForTag(string(ref.Name())); err == nil {
+			tagged[c.Hash] = append(tagged[c.Hash], short)
+		}
+	}
+
+	seen := map[plumbing.Hash]bool{co.Hash: true}
+	queue := []plumbing.Hash{co.Hash}
+	for len(queue) > 0 {
+		h := queue[0]
+		queue = queue[1:]
+		if tags := tagged[h]; len(tags) > 0 {
+			sortTags(tags)
+			tagCommit, err := r.git.CommitObject(h)
+			if err != nil {
+				return nil, err
+			}
+			logs, err := r.changelog(tagCommit, co)
+			if err != nil {
+				return nil, err
+			}
+
+			d := &Description{Tag: tags[0], Distance: len(logs), Hash: co.Hash.String()}
+			if dirty {
+				if d.Dirty, err = r.Dirty(); err != nil {
+					return nil, err
+				}
+			}
+			return d, nil
+		}
+
+		c, err := r.git.CommitObject(h)
+		if err != nil {
+			return nil, fmt.Errorf("could not walk history of %s: %w", h, err)
+		}
+		parents := c.ParentHashes
+		if r.FirstParent && len(parents) > 1 {
+			parents = parents[:1]
+		}
+		for _, p := range parents {
+			if !seen[p] {
+				seen[p] = true
+				queue = append(queue, p)
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("%w before %s", ErrNoTags, co.Hash.String()[:7])
+}
+
+// Dirty reports whether the working tree has staged or unstaged changes to tracked files. Untracked files are
+// ignored, as git describe --dirty does, and a bare repository is never dirty.
+func (r *Repo) Dirty() (bool, error) {
+	wt, err := r.git.Worktree()
+	if errors.Is(err, git.ErrIsBareRepository) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	status, err := wt.Status()
+	if err != nil {
+		return false, fmt.Errorf("could not read worktree status: %w", err)
+	}
+	for _, s := range status {
+		if s.Staging == git.Untracked && s.Worktree == git.Untracked {
+			continue
+		}
+		if s.Staging != git.Unmodified || s.Worktree != git.Unmodified {
+			return true, nil
+		}
+	}
+	return false, nil
+}
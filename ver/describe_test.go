@@ -0,0 +1,56 @@
+package ver
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-billy/v5/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepoDescribe(t *testing.T) {
+	r, s := newTestRepo(t)
+	reg := regexp.MustCompile(`^2024\.\d{2}\.\d{2}(-\w+)?$`)
+	day := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	_, err := r.Describe(reg, "", false)
+	assert.Error(t, err)
+
+	c1 := testCommit(t, r, "first", day)
+	testTag(t, s, "2024.01.01", c1)
+	testCommit(t, r, "second", day.Add(time.Hour))
+	c3 := testCommit(t, r, "third", day.Add(2*time.Hour))
+
+	d, err := r.Describe(reg, "", false)
+	require.NoError(t, err)
+	assert.Equal(t, "2024.01.01-2-g"+c3.String()[:7], d.String())
+
+	d, err = r.Describe(reg, c1.String(), false)
+	require.NoError(t, err)
+	assert.Equal(t, "2024.01.01", d.String())
+}
+
+func TestRepoDescribeDirty(t *testing.T) {
+	r, s := newTestRepo(t)
+	reg := regexp.MustCompile(`^2024\.\d{2}\.\d{2}(-\w+)?$`)
+	wt, err := r.Git().Worktree()
+	require.NoError(t, err)
+
+	require.NoError(t, util.WriteFile(wt.Filesystem, "file", []byte("a"), 0644))
+	_, err = wt.Add("file")
+	require.NoError(t, err)
+	c1 := testCommit(t, r, "first", time.Now())
+	testTag(t, s, "2024.01.01", c1)
+
+	require.NoError(t, util.WriteFile(wt.Filesystem, "untracked", []byte("a"), 0644))
+	d, err := r.Describe(reg, "", true)
+	require.NoError(t, err)
+	assert.Equal(t, "2024.01.01", d.String())
+
+	require.NoError(t, util.WriteFile(wt.Filesystem, "file", []byte("b"), 0644))
+	d, err = r.Describe(reg, "", true)
+	require.NoError(t, err)
+	assert.Equal(t, "2024.01.01-dirty", d.String())
+}
@@ -0,0 +1,156 @@
+package ver
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// ReleaseDiff summarises the changes between two releases.
+type ReleaseDiff struct {
+	From     string        `json:"from" yaml:"from"`
+	To       string        `json:"to" yaml:"to"`
+	FromHash string        `json:"from_hash" yaml:"from_hash"`
+	ToHash   string        `json:"to_hash" yaml:"to_hash"`
+	Commits  []ChangeEntry `json:"commits" yaml:"commits"`
+	Authors  []AuthorStat  `json:"authors" yaml:"authors"`
+	Files    []FileStat    `json:"files" yaml:"files"`
+	// Additions and Deletions are the line totals over Files.
+	Additions int `json:"additions" yaml:"additions"`
+	Deletions int `json:"deletions" yaml:"deletions"`
+}
+
+// AuthorStat counts the commits of one author in a ReleaseDiff.
+type AuthorStat struct {
+	Name    string `json:"name" yaml:"name"`
+	Email   string `json:"email" yaml:"email"`
+	Commits int    `json:"commits" yaml:"commits"`
+}
+
+// FileStat counts the lines added and removed in one file of a ReleaseDiff.
+type FileStat struct {
+	Name      string `json:"name" yaml:"name"`
+	Additions int    `json:"additions" yaml:"additions"`
+	Deletions int    `json:"deletions" yaml:"deletions"`
+}
+
+// Diff compares two revisions, typically release tags. Commits are those reachable from to but not from, newest
+// first, and file statistics come from the tree diff between the two commits.
+func (r *Repo) Diff(from, to string) (*ReleaseDiff, error) {
+	fromCommit, err := r.ResolveCommit(from)
+	if err != nil {
+		return nil, err
+	}
+	toCommit, err := r.ResolveCommit(to)
+	if err != nil {
+		return nil, err
+	}
+
+	logs, err := r.changelog(fromCommit, toCommit)
+	if err != nil {
+		return nil, err
+	}
+	d := &ReleaseDiff{
+		From:     from,
+		To:       to,
+		FromHash: fromCommit.Hash.String(),
+		ToHash:   toCommit.Hash.String(),
+		Commits:  make([]ChangeEntry, 0, len(logs)),
+		Authors:  make([]AuthorStat, 0),
+		Files:    make([]FileStat, 0),
+	}
+
+	authors := make(map[string]*AuthorStat)
+	for _, c := range logs {
+		d.Commits = append(d.Commits, newChangeEntry(c))
+		a := authors[c.Author.Email]
+		if a == nil {
+			a = &AuthorStat{Name: c.Author.Name, Email: c.Author.Email}
+			authors[c.Author.Email] = a
+		}
+		a.Commits++
+	}
+	for _, a := range authors {
+		d.Authors = append(d.Authors, *a)
+	}
+	sort.Slice(d.Authors, func(i, j int) bool {
+		if d.Authors[i].Commits != d.Authors[j].Commits {
+			return d.Authors[i].Commits > d.Authors[j].Commits
+		}
+		return d.Authors[i].Name < d.Authors[j].Name
+	})
+
+	patch, err := fromCommit.Patch(toCommit)
+	if err != nil {
+		return nil, fmt.Errorf("could not diff %s and %s: %w", from, to, err)
+	}
+	for _, s := range patch.Stats() {
+		d.Files = append(d.Files, FileStat{Name: s.Name, Additions: s.Addition, Deletions: s.Deletion})
+		d.Additions += s.Addition
+		d.Deletions += s.Deletion
+	}
+	sort.Slice(d.Files, func(i, j int) bool {
+		return d.Files[i].Name < d.Files[j].Name
+	})
+
+	return d, nil
+}
+
+// Summary is a one line description of the size of the diff.
+func (d *ReleaseDiff) Summary() string {
+	return fmt.Sprintf("%s, %s, %s changed, %d insertions(+), %d deletions(-)",
+		plural(len(d.Commits), "commit"), plural(len(d.Authors), "author"), plural(len(d.Files), "file"),
+		d.Additions, d.Deletions)
+}
+
+// WriteText writes the diff as plain text.
+func (d *ReleaseDiff) WriteText(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s..%s: %s\n", d.From, d.To, d.Summary())
+
+	b.WriteString("\nCommits:\n")
+	for _, c := range d.Commits {
+		fmt.Fprintf(&b, "  %s %s (%s)\n", c.Hash[:7], c.Subject, c.Author)
+	}
+	b.WriteString("\nAuthors:\n")
+	for _, a := range d.Authors {
+		fmt.Fprintf(&b, "  %s <%s> (%s)\n", a.Name, a.Email, plural(a.Commits, "commit"))
+	}
+	b.WriteString("\nFiles:\n")
+	for _, f := range d.Files {
+		fmt.Fprintf(&b, "  %s +%d -%d\n", f.Name, f.Additions, f.Deletions)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteMarkdown writes the diff as a Markdown section, for release notes and pull request descriptions.
+func (d *ReleaseDiff) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## %s...%s\n\n%s\n", d.From, d.To, d.Summary())
+
+	b.WriteString("\n### Commits\n\n")
+	for _, c := range d.Commits {
+		fmt.Fprintf(&b, "- %s (%s) by %s\n", c.Subject, c.Hash[:7], c.Author)
+	}
+	b.WriteString("\n### Authors\n\n")
+	for _, a := range d.Authors {
+		fmt.Fprintf(&b, "- %s (%s)\n", a.Name, plural(a.Commits, "commit"))
+	}
+	b.WriteString("\n### Files\n\n| File | Added | Removed |\n| --- | ---: | ---: |\n")
+	for _, f := range d.Files {
+		fmt.Fprintf(&b, "| `%s` | %d | %d |\n", f.Name, f.Additions, f.Deletions)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
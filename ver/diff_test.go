@@ -0,0 +1,44 @@
+package ver
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-billy/v5/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepoDiff(t *testing.T) {
+	r, s := newTestRepo(t)
+	wt, err := r.Git().Worktree()
+	require.NoError(t, err)
+	day := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	require.NoError(t, util.WriteFile(wt.Filesystem, "a.txt", []byte("one\n"), 0644))
+	_, err = wt.Add("a.txt")
+	require.NoError(t, err)
+	testTag(t, s, "2024.01.01", testCommit(t, r, "first", day))
+
+	require.NoError(t, util.WriteFile(wt.Filesystem, "a.txt", []byte("two\nthree\n"), 0644))
+	require.NoError(t, util.WriteFile(wt.Filesystem, "b.txt", []byte("new\n"), 0644))
+	_, err = wt.Add(".")
+	require.NoError(t, err)
+	testCommit(t, r, "feat: second", day.Add(time.Hour))
+	testTag(t, s, "2024.01.02", testCommit(t, r, "fix: third", day.Add(2*time.Hour)))
+
+	d, err := r.Diff("2024.01.01", "2024.01.02")
+	require.NoError(t, err)
+	require.Len(t, d.Commits, 2)
+	assert.Equal(t, "fix: third", d.Commits[0].Subject)
+	assert.Equal(t, []AuthorStat{{Name: "dev", Email: "dev@example.com", Commits: 2}}, d.Authors)
+	assert.Equal(t, []FileStat{{Name: "a.txt", Additions: 2, Deletions: 1}, {Name: "b.txt", Additions: 1}}, d.Files)
+	assert.Equal(t, 3, d.Additions)
+	assert.Equal(t, 1, d.Deletions)
+
+	var b strings.Builder
+	require.NoError(t, d.WriteMarkdown(&b))
+	assert.Contains(t, b.String(), "## 2024.01.01...2024.01.02\n\n2 commits, 1 author, 2 files changed")
+	assert.Contains(t, b.String(), "| `a.txt` | 2 | 1 |\n")
+}
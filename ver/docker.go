@@ -0,0 +1,147 @@
+package ver
+
+import (
+	"errors"
+	"math"
+)
+
+// maxImageTagLength is the longest tag an OCI registry accepts.
+const maxImageTagLength = 128
+
+// BakeTargetName is the bake target image tags are written to. It matches docker/metadata-action, so bake files
+// can inherit from it either way.
+const BakeTargetName = "docker-metadata-action"
+
+// ImageTags are the container image tags for one commit.
+type ImageTags struct {
+	Version string `json:"version" yaml:"version"`
+	Hash    string `json:"hash" yaml:"hash"`
+	// Released is set when the commit carries the release tag; otherwise Version is its describe string.
+	Released bool     `json:"released" yaml:"released"`
+	Tags     []string `json:"tags" yaml:"tags"`
+}
+
+// BakeFile is a docker buildx bake file holding only the image tags and labels of a target.
+type BakeFile struct {
+	Target map[string]BakeTarget `json:"target"`
+}
+
+// BakeTarget is a target of a BakeFile.
+type BakeTarget struct {
+	Tags   []string          `json:"tags"`
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// SanitizeImageTag maps s onto the OCI tag charset, [A-Za-z0-9_][A-Za-z0-9_.-]{0,127}. Other characters, such
+// as + and /, become -, a leading . or - becomes _, and the tag is cut to 128 characters.
+func SanitizeImageTag(s string) string {
+	b := []byte(s)
+	for i, c := range b {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '_':
+		case c == '.' || c == '-':
+			if i == 0 {
+				b[i] = '_'
+			}
+		default:
+			b[i] = '-'
+		}
+	}
+	if len(b) > maxImageTagLength {
+		b = b[:maxImageTagLength]
+	}
+	return string(b)
+}
+
+// ImageTags returns the image tags for rev (HEAD when empty). For a release, that is the newest release tag
+// of cv on rev, these are the version, its calendar prefixes and channels, and sha-<short hash>; as with alias
+// tags, prefixes and channels are only included when the version is the newest final release carrying them,
+// so a pre-release only gets its version and sha-<short hash>. An unreleased commit only gets its describe
+// string, eg. 2024.03.15-7-gabc1234, or the version cv would tag next when there is no earlier release, and
+// sha-<short hash>, so development builds never publish channels. Component prefixes are dropped, as each
+// component is its own image.
+func (r *Repo) ImageTags(cv *CalVer, rev string, channels []string) (*ImageTags, error) {
+	co, err := r.ResolveCommit(rev)
+	if err != nil {
+		return nil, err
+	}
+	reg := cv.PatchRegex()
+
+	res := &ImageTags{Hash: co.Hash.String()}
+	tags, err := r.TagsAt(reg, co.Hash.String())
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0)
+	if len(tags) > 0 {
+		res.Version, res.Released = tags[0], true
+		groups, err := r.List(reg, math.MaxInt, false)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, res.Version)
+		if !preRelease(res.Version) {
+			for _, a := range PrefixAliases(res.Version) {
+				if newestWith(groups, res.Version, a) {
+					names = append(names, a)
+				}
+			}
+			if newestWith(groups, res.Version, "") {
+				names = append(names, ChannelAliases(res.Version, channels)...)
+			}
+		}
+	} else {
+		d, err := r.Describe(reg, co.Hash.String(), false)
+		switch {
+		case err == nil:
+			res.Version = d.String()
+		case errors.Is(err, ErrNoTags):
+			if res.Version, _, err = r.NextVersion(cv); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, err
+		}
+		names = append(names, res.Version)
+	}
+
+	seen := make(map[string]bool)
+	for _, n := range names {
+		_, n = SplitComponent(n)
+		if t := SanitizeImageTag(n); t != "" && !seen[t] {
+			seen[t] = true
+			res.Tags = append(res.Tags, t)
+		}
+	}
+	res.Tags = append(res.Tags, "sha-"+res.Hash[:7])
+	return res, nil
+}
+
+// Refs returns the tags as references of each image, eg. ghcr.io/org/app:2024.03.15. Without images, the bare
+// tags are returned.
+func (t *ImageTags) Refs(images []string) []string {
+	if len(images) == 0 {
+		return t.Tags
+	}
+	refs := make([]string, 0, len(images)*len(t.Tags))
+	for _, image := range images {
+		for _, tag := range t.Tags {
+			refs = append(refs, image+":"+tag)
+		}
+	}
+	return refs
+}
+
+// Bake returns a bake file whose BakeTargetName target carries the image references and the OCI version and
+// revision labels.
+func (t *ImageTags) Bake(images []string) BakeFile {
+	return BakeFile{Target: map[string]BakeTarget{
+		BakeTargetName: {
+			Tags: t.Refs(images),
+			Labels: map[string]string{
+				"org.opencontainers.image.version":  t.Version,
+				"org.opencontainers.image.revision": t.Hash,
+			},
+		},
+	}}
+}
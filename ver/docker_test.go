@@ -0,0 +1,85 @@
+package ver
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSanitizeImageTag(t *testing.T) {
+	assert.Equal(t, "2024.03.15-rc-1", SanitizeImageTag("2024.03.15-rc+1"))
+	assert.Equal(t, "api-2024", SanitizeImageTag("api/2024"))
+	assert.Equal(t, "_dev", SanitizeImageTag(".dev"))
+	assert.Len(t, SanitizeImageTag(strings.Repeat("a", 200)), 128)
+}
+
+func TestRepoImageTags(t *testing.T) {
+	r, s := newTestRepo(t)
+	f, err := NewFormat("YYYY.0M.0D")
+	require.NoError(t, err)
+	cv := &CalVer{Format: f}
+	day := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+
+	c1 := testCommit(t, r, "first", day)
+	testTag(t, s, "2024.03.15", c1)
+	c2 := testCommit(t, r, "second", day.Add(24*time.Hour))
+	testTag(t, s, "2024.04.01", c2)
+
+	tags, err := r.ImageTags(cv, "", []string{"stable"})
+	require.NoError(t, err)
+	assert.True(t, tags.Released)
+	assert.Equal(t, []string{"2024.04.01", "2024", "2024.04", "stable", "sha-" + c2.String()[:7]}, tags.Tags)
+	assert.Equal(t, []string{"app:2024.04.01", "app:2024"}, tags.Refs([]string{"app"})[:2])
+
+	// An older release keeps only the tags it is the newest for.
+	tags, err = r.ImageTags(cv, c1.String(), []string{"stable"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2024.03.15", "2024.03", "sha-" + c1.String()[:7]}, tags.Tags)
+
+	// An unreleased commit gets neither prefixes nor channels.
+	c3 := testCommit(t, r, "third", time.Now())
+	tags, err = r.ImageTags(cv, "", []string{"stable"})
+	require.NoError(t, err)
+	assert.False(t, tags.Released)
+	short := c3.String()[:7]
+	assert.Equal(t, "2024.04.01-1-g"+short, tags.Version)
+	assert.Equal(t, []string{"2024.04.01-1-g" + short, "sha-" + short}, tags.Tags)
+}
+
+func TestRepoImageTagsPreRelease(t *testing.T) {
+	r, s := newTestRepo(t)
+	f, err := NewFormat("YYYY.0M.0D")
+	require.NoError(t, err)
+	cv := &CalVer{Format: f}
+	day := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+
+	c1 := testCommit(t, r, "release", day)
+	testTag(t, s, "2024.03.15", c1)
+	c2 := testCommit(t, r, "dev build", day.Add(24*time.Hour))
+	testTag(t, s, "2024.03.16-dev.2", c2)
+
+	tags, err := r.ImageTags(cv, "", []string{"stable"})
+	require.NoError(t, err)
+	assert.True(t, tags.Released)
+	assert.Equal(t, []string{"2024.03.16-dev.2", "sha-" + c2.String()[:7]}, tags.Tags)
+
+	// The final release keeps its prefixes and channels.
+	tags, err = r.ImageTags(cv, c1.String(), []string{"stable"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2024.03.15", "2024", "2024.03", "stable", "sha-" + c1.String()[:7]}, tags.Tags)
+}
+
+func TestRepoImageTagsFirstRelease(t *testing.T) {
+	r, _ := newTestRepo(t)
+	f, err := NewFormat("YYYY.0M.0D")
+	require.NoError(t, err)
+	h := testCommit(t, r, "first", time.Now())
+
+	tags, err := r.ImageTags(&CalVer{Format: f}, "", []string{"stable"})
+	require.NoError(t, err)
+	assert.False(t, tags.Released)
+	assert.Equal(t, []string{time.Now().Format("2006.01.02"), "sha-" + h.String()[:7]}, tags.Tags)
+}
@@ -1,11 +1,7 @@
 package ver
 
 import (
-	"bytes"
-	"errors"
 	"fmt"
-	"log"
-	"os/exec"
 	"regexp"
 	"sort"
 	"strconv"
@@ -13,113 +9,168 @@ import (
 	"time"
 
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
-	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
 )
 
-var ErrNotInRepo = errors.New("no repo found")
+// RepoPath is the repository the package-level functions operate on. It may be a working tree or any directory
+// inside one, a linked worktree, or a bare repository. Empty means the current directory. Tools embedding this
+// package should prefer Open and the methods on Repo.
+var RepoPath = ""
 
-// getGitRootDir executes `git rev-parse --show-toplevel` and returns the root directory as a string
-func getGitRootDir() (string, error) {
-	cmd := exec.Command("git", "rev-parse", "--show-toplevel")
-
-	var out bytes.Buffer
-	cmd.Stdout = &out
+type TagArgs struct {
+	CV     *CalVer
+	Hash   string
+	Push   bool
+	Tag    string
+	DryRun bool
+	// IfUntagged returns the tag already on the commit, when it has one matching CV, instead of creating another.
+	IfUntagged bool
+	// RequireChanges refuses to tag when there are no commits since the latest tag matching CV.
+	RequireChanges bool
+	// Force allows a tag that does not order above the latest tag of the format.
+	Force bool
+	// Aliases also moves the prefix aliases of the new version (eg. 2024 and 2024.03) and its Channels to it.
+	Aliases bool
+	// Channels are named alias tags, such as stable, moved along with the prefix aliases.
+	Channels []string
+}
 
-	err := cmd.Run()
+func GetRepoFormat() (*Format, bool, error) {
+	r, err := Open(RepoPath)
 	if err != nil {
-		return "", err
+		return nil, false, err
 	}
-
-	// Trim any trailing newline or spaces
-	return strings.TrimSpace(out.String()), nil
+	return r.Format()
 }
-func GetRepoFormat() (*Format, bool, error) {
-	p, err := getGitRootDir()
+
+// GetComponentFormat is Repo.ComponentFormat on RepoPath.
+func GetComponentFormat(component string) (*Format, bool, error) {
+	r, err := Open(RepoPath)
 	if err != nil {
-		return nil, false, ErrNotInRepo
+		return nil, false, err
 	}
-	r, err := git.PlainOpen(p)
+	return r.ComponentFormat(component)
+}
+
+func SetRepoFormat(f *Format) error {
+	r, err := Open(RepoPath)
 	if err != nil {
-		return nil, false, fmt.Errorf("could not init repo at .: %w", err)
+		return err
 	}
+	return r.SetFormat(f)
+}
 
-	conf, err := r.Config()
+func LatestTag(reg *regexp.Regexp, changelog bool) (*CalVerTagGroup, error) {
+	r, err := Open(RepoPath)
 	if err != nil {
-		return nil, false, fmt.Errorf("could not retrieve config: %w", err)
+		return nil, err
 	}
+	return r.Latest(reg, changelog)
+}
 
-	if !conf.Raw.HasSection("calver") {
-		return nil, false, fmt.Errorf("[calver] not set")
+func GetLatestAutoInc(cv *CalVer) (int, error) {
+	r, err := Open(RepoPath)
+	if err != nil {
+		return 1, nil // If no repo found, start at 1
 	}
+	return r.NextAutoInc(cv)
+}
 
-	val := conf.Raw.Section("calver").Option("format")
-	if val == "" {
-		return nil, false, fmt.Errorf("[calver].format not set")
+func ListTags(reg *regexp.Regexp, limit int, changelog bool) ([]*CalVerTagGroup, error) {
+	r, err := Open(RepoPath)
+	if err != nil {
+		return nil, err
 	}
+	return r.List(reg, limit, changelog)
+}
 
-	f, err := NewFormat(val)
+func VerifyHash(hash string) (string, error) {
+	r, err := Open(RepoPath)
 	if err != nil {
-		return nil, false, err
+		return "", err
+	}
+	co, err := r.ResolveCommit(hash)
+	if err != nil {
+		return "", err
 	}
-	return f, strings.HasSuffix(val, "-AUTO"), nil
+	return co.Hash.String(), nil
 }
 
-func SetRepoFormat(f *Format) error {
-	p, err := getGitRootDir()
+// TagNext is Repo.Tag on RepoPath. It refuses a version that is not greater than the latest tag unless
+// args.Force is set, and returns the short hash of the tagged commit.
+func TagNext(args TagArgs) (string, error) {
+	r, err := Open(RepoPath)
 	if err != nil {
-		return ErrNotInRepo
+		return "", err
 	}
-	r, err := git.PlainOpen(p)
+	res, err := r.Tag(args)
 	if err != nil {
-		return fmt.Errorf("could not init repo at .: %w", err)
+		return "", err
 	}
+	return res.ShortHash(), nil
+}
 
-	conf, err := r.Config()
+// TagAndPush is Repo.TagAndPush on RepoPath. It returns the tag that landed on origin and its short hash.
+func TagAndPush(args TagArgs, retries int) (string, string, error) {
+	r, err := Open(RepoPath)
 	if err != nil {
-		return fmt.Errorf("could not retrieve config: %w", err)
+		return "", "", err
 	}
-
-	conf.Raw.SetOption("calver", "", "format", f.String())
-	err = r.SetConfig(conf)
+	res, err := r.TagAndPush(args, retries)
 	if err != nil {
-		return err
+		return "", "", err
 	}
-
-	return nil
+	return res.Tag, res.ShortHash(), nil
 }
 
-type TagArgs struct {
-	CV   *CalVer
-	Hash string
-	Push bool
-	Tag  string
+func Untag(args TagArgs) error {
+	r, err := Open(RepoPath)
+	if err != nil {
+		return err
+	}
+	_, err = r.Untag(args)
+	return err
 }
 
-func LatestTag(reg *regexp.Regexp, changelog bool) (*CalVerTagGroup, error) {
-	latestList, err := ListTags(reg, 1, changelog)
+func Retag(args TagArgs) (string, error) {
+	r, err := Open(RepoPath)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
-	for _, tagGroup := range latestList {
-		return tagGroup, nil
+	_, res, err := r.Retag(args)
+	if err != nil {
+		return "", err
 	}
+	return res.ShortHash(), nil
+}
 
-	return nil, fmt.Errorf("no latest tag found")
+func TagExists(tag string) bool {
+	r, err := Open(RepoPath)
+	if err != nil {
+		return false
+	}
+	return r.TagExists(tag)
 }
 
-func GetLatestAutoInc(cv *CalVer) (int, error) {
-	// Get all tags that match the format, not just the latest group
-	allTags, err := ListTags(cv.Regex(), 100, false) // Get more tags to search through
+// GetLatestRemoteAutoInc is GetLatestAutoInc for tags read from a remote URL.
+func GetLatestRemoteAutoInc(cv *CalVer, url string) (int, error) {
+	allTags, err := ListRemoteTags(url, cv.Regex(), 100)
 	if err != nil {
-		return 1, nil // If no tags found, start at 1
+		return 0, err
 	}
 
+	return nextAutoInc(cv, allTags)
+}
+
+func nextAutoInc(cv *CalVer, allTags []*CalVerTagGroup) (int, error) {
 	nextTagStr, err := cv.Version(time.Now())
 	if err != nil {
 		return 0, fmt.Errorf("could not generate next version: %w", err)
 	}
 
+	_, nextTagStr = SplitComponent(nextTagStr)
 	nextCalVer := strings.Split(nextTagStr, "-")[0]
 	maxInc := 0
 	foundMatchingTag := false
@@ -127,6 +178,7 @@ func GetLatestAutoInc(cv *CalVer) (int, error) {
 	// Search through all tag groups for matching versions
 	for _, tagGroup := range allTags {
 		for _, tag := range tagGroup.Tags {
+			_, tag = SplitComponent(tag)
 			if !strings.HasPrefix(tag, nextCalVer) {
 				// No matching tag found to increment
 				continue
@@ -160,125 +212,77 @@ func GetLatestAutoInc(cv *CalVer) (int, error) {
 	return 1, nil
 }
 
-func ListTags(reg *regexp.Regexp, limit int, changelog bool) ([]*CalVerTagGroup, error) {
-	p, err := getGitRootDir()
-	if err != nil {
-		return nil, ErrNotInRepo
-	}
-	r, err := git.PlainOpen(p)
+// ListRemoteTags lists calver tags straight from a remote URL (file://, ssh or https) without a local clone.
+// Only ref information is available, so the returned groups carry hashes but no commits or changelog.
+func ListRemoteTags(url string, reg *regexp.Regexp, limit int) ([]*CalVerTagGroup, error) {
+	remote := git.NewRemote(memory.NewStorage(), &config.RemoteConfig{
+		Name: "origin",
+		URLs: []string{url},
+	})
+
+	refs, err := remote.List(&git.ListOptions{PeelingOption: git.AppendPeeled})
 	if err != nil {
-		return nil, fmt.Errorf("could not init repo at .: %w", err)
+		return nil, fmt.Errorf("could not list remote %s: %w", url, err)
 	}
 
-	refs, err := r.Tags()
-	if err != nil {
-		return nil, fmt.Errorf("could not find ags: %w", err)
+	// Annotated tags are listed twice: once for the tag object and once, suffixed with ^{}, for the commit.
+	peeled := make(map[string]plumbing.Hash)
+	for _, ref := range refs {
+		name := ref.Name().String()
+		if ref.Name().IsTag() && strings.HasSuffix(name, "^{}") {
+			peeled[strings.TrimSuffix(name, "^{}")] = ref.Hash()
+		}
 	}
 
 	tagMap := make(map[string]*CalVerTagGroup)
 	tags := make([]string, 0)
-	//hashes := make([]string, 0)
-	err = refs.ForEach(func(tag *plumbing.Reference) error {
-		short := tag.Name().Short()
-		if !reg.Match([]byte(short)) {
-			return nil
+	for _, ref := range refs {
+		if !ref.Name().IsTag() || strings.HasSuffix(ref.Name().String(), "^{}") {
+			continue
 		}
-		co, _ := getCommitByTag(r, string(tag.Name()))
-		if co == nil {
-			return nil
+		short := ref.Name().Short()
+		if !reg.MatchString(short) || tagMap[short] != nil {
+			continue
 		}
 
-		hash := co.Hash.String()[:7]
-		inc := func(l []string, s string) bool {
-			for _, c := range l {
-				if c == s {
-					return true
-				}
-			}
-			return false
-		}
-		if !inc(tags, short) {
-			tags = append(tags, short)
+		h := ref.Hash()
+		if p, ok := peeled[ref.Name().String()]; ok {
+			h = p
 		}
 		tags = append(tags, short)
-
-		if tagMap[short] == nil {
-			tagMap[short] = &CalVerTagGroup{
-				Hash:      hash,
-				Commit:    co,
-				When:      co.Author.When,
-				Tags:      []string{short},
-				Refs:      []*plumbing.Reference{tag},
-				ChangeLog: []*object.Commit{co},
-			}
-			return nil
+		tagMap[short] = &CalVerTagGroup{
+			Hash: h.String()[:7],
+			Tags: []string{short},
+			Refs: []*plumbing.Reference{plumbing.NewHashReference(ref.Name(), h)},
 		}
+	}
 
-		tagMap[short].Tags = append(tagMap[short].Tags, short)
-		tagMap[short].Refs = append(tagMap[short].Refs, tag)
-		return nil
-	})
+	sortTags(tags)
+	return collectGroups(tagMap, tags, limit), nil
+}
+
+// LatestRemoteTag returns the newest calver tag on a remote URL.
+func LatestRemoteTag(url string, reg *regexp.Regexp) (*CalVerTagGroup, error) {
+	latestList, err := ListRemoteTags(url, reg, 1)
 	if err != nil {
 		return nil, err
 	}
+	for _, tagGroup := range latestList {
+		return tagGroup, nil
+	}
 
-	sort.Slice(tags, func(i, j int) bool {
-		a := tags[i]
-		b := tags[j]
-		aBits := strings.Split(a, "-")
-		bBits := strings.Split(b, "-")
-		if aBits[0] != bBits[0] {
-			return bBits[0] < aBits[0]
-		}
-
-		if len(aBits) > 1 {
-			if aMod, err := strconv.Atoi(aBits[1]); err == nil {
-				if bMod, err := strconv.Atoi(bBits[1]); err == nil {
-					return bMod < aMod
-				}
-			}
-		}
+	return nil, fmt.Errorf("no latest tag found")
+}
 
-		return tags[j] < tags[i]
+// sortTags orders calver tag names newest first, comparing numeric modifiers numerically.
+func sortTags(tags []string) {
+	sort.SliceStable(tags, func(i, j int) bool {
+		return CompareVersions(tags[i], tags[j]) > 0
 	})
+}
 
-	if changelog {
-		changeLimit := 20
-		for i, hash := range tags {
-			since := time.Time{}
-			if i < len(tags)-2 {
-				since = tagMap[tags[i+1]].Commit.Author.When.Add(time.Second * 1)
-			}
-
-			logs, _ := r.Log(&git.LogOptions{
-				Order: git.LogOrderCommitterTime,
-				Since: &since,
-				Until: &tagMap[hash].Commit.Author.When,
-			})
-			if logs == nil {
-				continue
-			}
-			includes := func(l []*object.Commit, commit *object.Commit) bool {
-				for _, c := range l {
-					if c.Hash.String() == commit.Hash.String() {
-						return true
-					}
-				}
-				return false
-			}
-			_ = logs.ForEach(func(commit *object.Commit) error {
-				if !includes(tagMap[hash].ChangeLog, commit) {
-					tagMap[hash].ChangeLog = append(tagMap[hash].ChangeLog, commit)
-				}
-				return nil
-			})
-			changeLimit++
-			if changeLimit > 20 {
-				break
-			}
-		}
-	}
-
+// collectGroups returns the groups for the sorted tags, marking the first as latest and stopping at limit.
+func collectGroups(tagMap map[string]*CalVerTagGroup, tags []string, limit int) []*CalVerTagGroup {
 	results := make([]*CalVerTagGroup, 0)
 	for i, tag := range tags {
 		if i == 0 {
@@ -291,272 +295,5 @@ func ListTags(reg *regexp.Regexp, limit int, changelog bool) ([]*CalVerTagGroup,
 		results = append(results, tagMap[tag])
 	}
 
-	return results, nil
-}
-
-func VerifyHash(hash string) (string, error) {
-	p, err := getGitRootDir()
-	if err != nil {
-		return "", ErrNotInRepo
-	}
-	r, err := git.PlainOpen(p)
-	if err != nil {
-		return "", fmt.Errorf("could not init repo at .: %w", err)
-	}
-	if hash == "" || hash == "HEAD" {
-		head, err := r.Head()
-		if err != nil {
-			return "", fmt.Errorf("cannot get HEAD: %w", err)
-		}
-		return head.Hash().String(), nil
-	} else if len(hash) < 8 {
-		co, _ := findShortHash(r, hash)
-		hash = co.Hash.String()
-	}
-
-	co, err := r.CommitObject(plumbing.NewHash(hash))
-	if err != nil {
-		return "", fmt.Errorf("cannot find hash %s", hash)
-	}
-	return co.Hash.String(), nil
-}
-
-func TagNext(args TagArgs) (string, error) {
-	p, err := getGitRootDir()
-	if err != nil {
-		return "", ErrNotInRepo
-	}
-	r, err := git.PlainOpen(p)
-	if err != nil {
-		return "", fmt.Errorf("could not init repo at .: %w", err)
-	}
-
-	v := args.Tag
-	if v == "" {
-		v, err = args.CV.Version(time.Now())
-		if err != nil {
-			return "", err
-		}
-	}
-
-	var co *object.Commit
-	if args.Hash == "" {
-		h, err := r.ResolveRevision("HEAD")
-		if err != nil {
-			return "", fmt.Errorf("could not resolve HEAD: %w", err)
-		}
-		co, err = r.CommitObject(*h)
-		if err != nil {
-			return "", err
-		}
-	} else {
-		co, err = findShortHash(r, args.Hash)
-		if err != nil {
-			return "", err
-		}
-	}
-
-	_, err = setTag(r, v, co)
-	if err != nil {
-		return "", fmt.Errorf("could not create tag: %w", err)
-	}
-
-	if args.Push {
-		err = pushTags(false, v)
-	}
-	if co == nil {
-		return "HEAD", err
-	}
-
-	return co.Hash.String()[:7], nil
-}
-
-func Untag(args TagArgs) error {
-	p, err := getGitRootDir()
-	if err != nil {
-		return ErrNotInRepo
-	}
-	r, err := git.PlainOpen(p)
-	if err != nil {
-		return fmt.Errorf("could not init repo at .: %w", err)
-	}
-
-	oldHash := tagHash(args.Tag)
-	err = r.DeleteTag(args.Tag)
-	if err != nil {
-		return fmt.Errorf("could not delete tag '%s': %w", args.Tag, err)
-	}
-
-	fmt.Printf("Deleted tag '%s' (hash %s)\n", args.Tag, oldHash[:7])
-
-	if args.Push {
-		return pushTags(true, args.Tag)
-	}
-	return nil
-}
-
-func Retag(args TagArgs) (string, error) {
-	err := Untag(args)
-	if err != nil {
-		return "", err
-	}
-
-	return TagNext(args)
-}
-
-func TagExists(tag string) bool {
-	p, err := getGitRootDir()
-	if err != nil {
-		return false
-	}
-	r, err := git.PlainOpen(p)
-	if err != nil {
-		return false
-	}
-	return tagExists(r, tag)
-}
-
-func findShortHash(r *git.Repository, hash string) (*object.Commit, error) {
-	cos, err := r.CommitObjects()
-	if err != nil {
-		return nil, err
-	}
-
-	var c *object.Commit
-	err = cos.ForEach(func(commit *object.Commit) error {
-		if strings.HasPrefix(commit.Hash.String(), hash) {
-			c = commit
-			return fmt.Errorf("found")
-		}
-		return nil
-	})
-	if err != nil && err.Error() != "found" {
-		return nil, err
-	}
-
-	return c, nil
-}
-
-func getCommitByTag(r *git.Repository, tag string) (*object.Commit, error) {
-	rev, _ := r.ResolveRevision(plumbing.Revision(tag))
-	co, err := r.CommitObject(plumbing.NewHash(rev.String()))
-	if err != nil {
-		return nil, err
-	}
-	return co, nil
-}
-
-func tagHash(tag string) string {
-	p, err := getGitRootDir()
-	if err != nil {
-		return ""
-	}
-	r, err := git.PlainOpen(p)
-	if err != nil {
-		return ""
-	}
-
-	ref, err := r.Tag(tag)
-	if err != nil {
-		return ""
-	}
-	co, err := getCommitByTag(r, string(ref.Name()))
-	if err != nil {
-		return ""
-	}
-	return co.Hash.String()
-}
-
-func tagExists(r *git.Repository, tag string) bool {
-	tagFoundErr := "tag was found"
-	tags, err := r.Tags()
-	if err != nil {
-		log.Printf("get tags error: %s", err)
-		return false
-	}
-	res := false
-	err = tags.ForEach(func(t *plumbing.Reference) error {
-		if t.Name().Short() == tag {
-			res = true
-			return fmt.Errorf(tagFoundErr)
-		}
-		return nil
-	})
-	if err != nil && err.Error() != tagFoundErr {
-		fmt.Printf("iterate ags error: %s", err)
-		return false
-	}
-	return res
-}
-
-func setTag(r *git.Repository, tag string, co *object.Commit) (bool, error) {
-	if tagExists(r, tag) {
-		fmt.Printf("tag %s already exists\n", tag)
-		return false, nil
-	}
-	if co == nil {
-		h, err := r.Head()
-		if err != nil {
-			fmt.Printf("get HEAD error: %s", err)
-			return false, err
-		}
-		co, err = findShortHash(r, h.Hash().String())
-		if err != nil {
-			fmt.Printf("get hash (%s) error: %s", h.Hash(), err)
-			return false, err
-		}
-	}
-
-	gitPathCmd := exec.Command("which", "git")
-	gitPath, err := gitPathCmd.Output()
-	if err != nil {
-		return false, fmt.Errorf("could not find git on host system, push is not supported")
-	}
-
-	gitCmd := strings.Replace(string(gitPath), "\n", "", -1)
-	cmd := exec.Command(gitCmd, "tag", tag)
-	_, err = cmd.Output()
-	if err != nil {
-		fmt.Println(err.Error())
-	}
-
-	if err != nil {
-		fmt.Printf("create tag error: %s\n", err)
-		return false, err
-	}
-
-	return true, nil
-}
-
-func pushTags(deletion bool, tag ...string) error {
-	if len(tag) == 0 {
-		return fmt.Errorf("no tags to push")
-	}
-	gitPathCmd := exec.Command("which", "git")
-	gitPath, err := gitPathCmd.Output()
-	if err != nil {
-		return fmt.Errorf("could not find git on host system, push is not supported")
-	}
-
-	gitCmd := strings.Replace(string(gitPath), "\n", "", -1)
-
-	for _, t := range tag {
-		action := fmt.Sprintf("pushing %s to origin\n", t)
-		cmd := exec.Command(gitCmd, "push", "origin", t)
-
-		if deletion {
-			cmd = exec.Command(gitCmd, "push", "origin", "--delete", t)
-			action = fmt.Sprintf("deleting %s on origin\n", t)
-		}
-		fmt.Printf(action)
-		stdout, err := cmd.Output()
-		if err != nil {
-			fmt.Println(err.Error())
-			continue
-		}
-
-		fmt.Println(string(stdout))
-	}
-
-	return nil
+	return results
 }
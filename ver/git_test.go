@@ -0,0 +1,13 @@
+package ver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortTags(t *testing.T) {
+	tags := []string{"2024.01-2", "2023.12", "2024.01-10", "2024.02", "2024.01"}
+	sortTags(tags)
+	assert.Equal(t, []string{"2024.02", "2024.01-10", "2024.01-2", "2024.01", "2023.12"}, tags)
+}
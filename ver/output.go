@@ -0,0 +1,261 @@
+package ver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+const (
+	// ActionCreated marks a tag that was created.
+	ActionCreated = "created"
+	// ActionDeleted marks a tag that was deleted.
+	ActionDeleted = "deleted"
+	// ActionRetagged marks a tag that was moved to another commit.
+	ActionRetagged = "retagged"
+	// ActionExisting marks a tag that was already on the commit, so nothing was created.
+	ActionExisting = "existing"
+	// ActionPromoted marks a release whose aliases were moved to it.
+	ActionPromoted = "promoted"
+)
+
+// ReleaseInfo is the machine-readable description of a release, used for JSON and YAML output.
+type ReleaseInfo struct {
+	Version      string        `json:"version" yaml:"version"`
+	Component    string        `json:"component,omitempty" yaml:"component,omitempty"`
+	Segments     []int         `json:"segments,omitempty" yaml:"segments,omitempty"`
+	Modifier     string        `json:"modifier,omitempty" yaml:"modifier,omitempty"`
+	Hash         string        `json:"hash,omitempty" yaml:"hash,omitempty"`
+	PreviousHash string        `json:"previous_hash,omitempty" yaml:"previous_hash,omitempty"`
+	Date         *time.Time    `json:"date,omitempty" yaml:"date,omitempty"`
+	Tags         []string      `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Latest       bool          `json:"latest,omitempty" yaml:"latest,omitempty"`
+	Action       string        `json:"action,omitempty" yaml:"action,omitempty"`
+	Pushed       bool          `json:"pushed,omitempty" yaml:"pushed,omitempty"`
+	DryRun       bool          `json:"dry_run,omitempty" yaml:"dry_run,omitempty"`
+	Remote       string        `json:"remote,omitempty" yaml:"remote,omitempty"`
+	RemoteURL    string        `json:"remote_url,omitempty" yaml:"remote_url,omitempty"`
+	Hooks        []string      `json:"hooks,omitempty" yaml:"hooks,omitempty"`
+	Aliases      []string      `json:"aliases,omitempty" yaml:"aliases,omitempty"`
+	Changelog    []ChangeEntry `json:"changelog,omitempty" yaml:"changelog,omitempty"`
+}
+
+// ChangeEntry is a single commit in a ReleaseInfo changelog.
+type ChangeEntry struct {
+	Hash     string    `json:"hash" yaml:"hash"`
+	Author   string    `json:"author" yaml:"author"`
+	Email    string    `json:"email" yaml:"email"`
+	Date     time.Time `json:"date" yaml:"date"`
+	Subject  string    `json:"subject" yaml:"subject"`
+	Type     string    `json:"type,omitempty" yaml:"type,omitempty"`
+	Scope    string    `json:"scope,omitempty" yaml:"scope,omitempty"`
+	Breaking bool      `json:"breaking,omitempty" yaml:"breaking,omitempty"`
+}
+
+// ParseVersion splits a calver version such as 2024.03.15-RC2 into its numeric segments and modifier. A
+// component prefix, as in api/2024.03.15-RC2, is ignored.
+func ParseVersion(v string) ([]int, string, error) {
+	_, version := SplitComponent(v)
+	calendar, modifier, _ := strings.Cut(version, "-")
+	segments := make([]int, 0, 3)
+	for _, s := range strings.Split(calendar, ".") {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid version segment '%s' in %s", s, v)
+		}
+		segments = append(segments, n)
+	}
+	return segments, modifier, nil
+}
+
+// CompareVersions orders two calver versions, returning -1, 0 or 1 when a is lower than, equal to or greater
+// than b. Segments compare numerically, so 2024.9 is lower than 2024.10. For equal calendar segments the
+// modifier decides, in three tiers:
+//
+//   - pre-releases, whose modifier starts with a letter (2024.03.15-dev.1, 2024.03.15-RC1), are lowest;
+//   - the final release without a modifier (2024.03.15) comes next;
+//   - numeric increments (2024.03.15-1, 2024.03.15-2) are highest, as they follow the final release.
+//
+// Modifiers compare part by part on dots, so the hotfix modifier 2.1 orders between 2 and 3. Within a part,
+// a trailing number compares numerically, so RC2 is lower than RC10 and -2 is lower than -10. Pre-release
+// names compare case-insensitively as dev < alpha < beta < rc, with other names before dev in alphabetical
+// order. An extra patch segment is compared last, after the modifier. Versions that cannot be parsed compare
+// as strings.
+func CompareVersions(a, b string) int {
+	aSegs, aMod, aErr := ParseVersion(a)
+	bSegs, bMod, bErr := ParseVersion(b)
+	if aErr != nil || bErr != nil {
+		return strings.Compare(a, b)
+	}
+
+	for i := 0; i < len(aSegs) && i < len(bSegs); i++ {
+		if aSegs[i] != bSegs[i] {
+			return compareInts(aSegs[i], bSegs[i])
+		}
+	}
+	// A patch segment, as in the hotfix 2024.03.15.1, only counts after the modifier, so the hotfix orders
+	// between 2024.03.15 and the same-day release 2024.03.15-1.
+	if c := compareModifiers(aMod, bMod); c != 0 {
+		return c
+	}
+	return compareInts(len(aSegs), len(bSegs))
+}
+
+// IsPreRelease reports whether a modifier marks a pre-release, such as RC1 or dev.3, rather than a numeric
+// increment.
+func IsPreRelease(modifier string) bool {
+	return modifier != "" && (modifier[0] < '0' || modifier[0] > '9')
+}
+
+// modifierStream names the series a modifier belongs to: the lower-cased name of a pre-release, such as dev
+// for dev.3 or rc for RC2, and "" for final releases and their numeric increments.
+func modifierStream(m string) string {
+	if !IsPreRelease(m) {
+		return ""
+	}
+	name, _, _ := strings.Cut(m, ".")
+	prefix, _ := splitModifier(name)
+	return strings.ToLower(prefix)
+}
+
+// modifierTier places a modifier in the pre-release, final or increment tier.
+func modifierTier(m string) int {
+	switch {
+	case m == "":
+		return 1
+	case IsPreRelease(m):
+		return 0
+	}
+	return 2
+}
+
+func compareModifiers(a, b string) int {
+	if c := compareInts(modifierTier(a), modifierTier(b)); c != 0 || a == "" {
+		return c
+	}
+	aParts := strings.Split(a, ".")
+	bParts := strings.Split(b, ".")
+	for i := 0; i < len(aParts) && i < len(bParts); i++ {
+		if c := compareModifierPart(aParts[i], bParts[i]); c != 0 {
+			return c
+		}
+	}
+	return compareInts(len(aParts), len(bParts))
+}
+
+// preReleaseRanks orders the well-known pre-release names; other names rank 0 and sort alphabetically.
+var preReleaseRanks = map[string]int{"dev": 1, "alpha": 2, "beta": 3, "rc": 4}
+
+func compareModifierPart(a, b string) int {
+	aPrefix, aNum := splitModifier(a)
+	bPrefix, bNum := splitModifier(b)
+	aPrefix, bPrefix = strings.ToLower(aPrefix), strings.ToLower(bPrefix)
+	if aPrefix == bPrefix {
+		return compareInts(aNum, bNum)
+	}
+	// A purely numeric part orders before a named one, as in semver.
+	if aPrefix == "" || bPrefix == "" {
+		return compareInts(len(aPrefix), len(bPrefix))
+	}
+	if c := compareInts(preReleaseRanks[aPrefix], preReleaseRanks[bPrefix]); c != 0 {
+		return c
+	}
+	return strings.Compare(aPrefix, bPrefix)
+}
+
+// splitModifier splits a modifier such as RC12 into its prefix and trailing number, which is -1 when absent.
+func splitModifier(m string) (string, int) {
+	i := len(m)
+	for i > 0 && m[i-1] >= '0' && m[i-1] <= '9' {
+		i--
+	}
+	n, err := strconv.Atoi(m[i:])
+	if err != nil {
+		return m, -1
+	}
+	return m[:i], n
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// NewReleaseInfo describes a version, filling in its parsed segments. A component prefix is split off into
+// Component.
+func NewReleaseInfo(version string) ReleaseInfo {
+	component, version := SplitComponent(version)
+	info := ReleaseInfo{Version: version, Component: component}
+	if segments, modifier, err := ParseVersion(version); err == nil {
+		info.Segments = segments
+		info.Modifier = modifier
+	}
+	return info
+}
+
+// Info describes the tag group.
+func (cvt *CalVerTagGroup) Info() ReleaseInfo {
+	info := NewReleaseInfo(cvt.Tag())
+	info.Hash = cvt.Hash
+	if len(cvt.Refs) > 0 {
+		// Remote listings have no commit, and cvt.Hash is abbreviated there.
+		info.Hash = cvt.Refs[0].Hash().String()
+	}
+	info.Tags = cvt.Tags
+	info.Latest = cvt.Latest
+	if cvt.Commit != nil {
+		info.Hash = cvt.Commit.Hash.String()
+		when := cvt.Time()
+		info.Date = &when
+	}
+	for _, c := range cvt.ChangeLog {
+		info.Changelog = append(info.Changelog, newChangeEntry(c))
+	}
+	return info
+}
+
+// Info describes the tag result, recording the action that produced it. A reused tag is always reported as
+// ActionExisting.
+func (t *TagResult) Info(action string) ReleaseInfo {
+	if t.Existing {
+		action = ActionExisting
+	}
+	info := NewReleaseInfo(t.Tag)
+	info.Hash = t.Hash
+	info.Tags = []string{t.Tag}
+	info.Action = action
+	info.Pushed = t.Pushed
+	info.DryRun = t.DryRun
+	info.Remote = t.Remote
+	info.RemoteURL = t.RemoteURL
+	info.Hooks = t.Hooks
+	info.Aliases = t.Aliases
+	if !t.When.IsZero() {
+		when := t.When
+		info.Date = &when
+	}
+	return info
+}
+
+func newChangeEntry(c *object.Commit) ChangeEntry {
+	cc := ParseConventionalCommit(c.Message)
+	subject := strings.SplitN(strings.TrimSpace(c.Message), "\n", 2)[0]
+	return ChangeEntry{
+		Hash:     c.Hash.String(),
+		Author:   c.Author.Name,
+		Email:    c.Author.Email,
+		Date:     c.Author.When,
+		Subject:  subject,
+		Type:     cc.Type,
+		Scope:    cc.Scope,
+		Breaking: cc.Breaking,
+	}
+}
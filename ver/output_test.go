@@ -0,0 +1,65 @@
+package ver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		version  string
+		segments []int
+		modifier string
+		wantErr  bool
+	}{
+		{version: "2024.03.15", segments: []int{2024, 3, 15}},
+		{version: "24.1-RC2", segments: []int{24, 1}, modifier: "RC2"},
+		{version: "2024.03.15-3", segments: []int{2024, 3, 15}, modifier: "3"},
+		{version: "v1.2.3", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.version, func(t *testing.T) {
+			segments, modifier, err := ParseVersion(test.version)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.segments, segments)
+			assert.Equal(t, test.modifier, modifier)
+		})
+	}
+}
+
+func TestTagResultInfo(t *testing.T) {
+	when := time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)
+	res := &TagResult{Tag: "2024.03.15-RC1", Hash: "abc1234def", When: when, Pushed: true}
+
+	info := res.Info(ActionCreated)
+	assert.Equal(t, ReleaseInfo{
+		Version:  "2024.03.15-RC1",
+		Segments: []int{2024, 3, 15},
+		Modifier: "RC1",
+		Hash:     "abc1234def",
+		Date:     &when,
+		Tags:     []string{"2024.03.15-RC1"},
+		Action:   ActionCreated,
+		Pushed:   true,
+	}, info)
+}
+
+func TestRemoteTagGroupInfo(t *testing.T) {
+	h := plumbing.NewHash("0123456789abcdef0123456789abcdef01234567")
+	g := &CalVerTagGroup{
+		Hash: h.String()[:7],
+		Tags: []string{"2024.03.15"},
+		Refs: []*plumbing.Reference{plumbing.NewHashReference(plumbing.NewTagReferenceName("2024.03.15"), h)},
+	}
+
+	// Remote listings report the full hash, as local ones do.
+	assert.Equal(t, h.String(), g.Info().Hash)
+}
@@ -0,0 +1,124 @@
+package ver
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// ErrBranchNotAllowed is returned when a branch policy forbids tagging on the current branch.
+var ErrBranchNotAllowed = errors.New("tagging is not allowed on this branch")
+
+// BranchPolicy is the tagging rule for branches matching Pattern, read from a [calver-branch "<pattern>"]
+// section of the git config, eg.
+//
+//	[calver-branch "develop"]
+//		modifier = dev.
+//		autoIncrement = true
+//	[calver-branch "feature/*"]
+//		allowed = false
+type BranchPolicy struct {
+	// Pattern is a glob as understood by path.Match, so * does not cross a /.
+	Pattern string
+	// Modifier is used when no modifier is given explicitly.
+	Modifier string
+	// AutoIncrement turns on auto-incrementing.
+	AutoIncrement bool
+	// Allowed is false for branches that must not be tagged.
+	Allowed bool
+}
+
+// BranchPolicies returns the branch policies in the order they appear in the config.
+func (r *Repo) BranchPolicies() ([]BranchPolicy, error) {
+	conf, err := r.git.Config()
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve config: %w", err)
+	}
+	if !conf.Raw.HasSection("calver-branch") {
+		return nil, nil
+	}
+
+	policies := make([]BranchPolicy, 0)
+	for _, sub := range conf.Raw.Section("calver-branch").Subsections {
+		p := BranchPolicy{Pattern: sub.Name, Modifier: sub.Options.Get("modifier"), Allowed: true}
+		if v := sub.Options.Get("autoIncrement"); v != "" {
+			if p.AutoIncrement, err = strconv.ParseBool(v); err != nil {
+				return nil, fmt.Errorf("invalid calver-branch.%s.autoIncrement '%s'", sub.Name, v)
+			}
+		}
+		if v := sub.Options.Get("allowed"); v != "" {
+			if p.Allowed, err = strconv.ParseBool(v); err != nil {
+				return nil, fmt.Errorf("invalid calver-branch.%s.allowed '%s'", sub.Name, v)
+			}
+		}
+		policies = append(policies, p)
+	}
+	return policies, nil
+}
+
+// MatchBranchPolicy returns the first policy whose pattern matches branch.
+func MatchBranchPolicy(policies []BranchPolicy, branch string) (*BranchPolicy, bool) {
+	for i, p := range policies {
+		if ok, _ := path.Match(p.Pattern, branch); ok {
+			return &policies[i], true
+		}
+	}
+	return nil, false
+}
+
+// CurrentBranch returns the short name of the branch checked out at HEAD, or an empty string when HEAD is
+// detached.
+func (r *Repo) CurrentBranch() (string, error) {
+	head, err := r.git.Head()
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("could not read HEAD: %w", err)
+	}
+	if !head.Name().IsBranch() {
+		return "", nil
+	}
+	return head.Name().Short(), nil
+}
+
+// Apply adjusts a for the policy. An explicit modifier in a is kept. A branch where tagging is not allowed is
+// refused unless a.AnyBranch is set.
+func (p *BranchPolicy) Apply(a *CalVerArgs, branch string) error {
+	if !p.Allowed && !a.AnyBranch {
+		return fmt.Errorf("%w: %s", ErrBranchNotAllowed, branch)
+	}
+	if a.Modifier == "" {
+		a.Modifier = p.Modifier
+	}
+	if p.AutoIncrement {
+		a.AutoIncrement = true
+	}
+	return nil
+}
+
+// applyBranchPolicy applies the policy for a.Branch, or the current branch when that is empty, unless
+// a.IgnoreBranchPolicy is set.
+func (r *Repo) applyBranchPolicy(a *CalVerArgs) error {
+	if a.IgnoreBranchPolicy {
+		return nil
+	}
+	policies, err := r.BranchPolicies()
+	if err != nil || len(policies) == 0 {
+		return err
+	}
+
+	branch := a.Branch
+	if branch == "" {
+		if branch, err = r.CurrentBranch(); err != nil || branch == "" {
+			return err
+		}
+	}
+	if p, ok := MatchBranchPolicy(policies, branch); ok {
+		return p.Apply(a, branch)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package ver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchBranchPolicy(t *testing.T) {
+	policies := []BranchPolicy{
+		{Pattern: "main", Allowed: true},
+		{Pattern: "feature/*", Allowed: false},
+		{Pattern: "*", Modifier: "dev.", AutoIncrement: true, Allowed: true},
+	}
+
+	p, ok := MatchBranchPolicy(policies, "feature/login")
+	require.True(t, ok)
+	assert.False(t, p.Allowed)
+	p, ok = MatchBranchPolicy(policies, "develop")
+	require.True(t, ok)
+	assert.Equal(t, "dev.", p.Modifier)
+	_, ok = MatchBranchPolicy(policies, "team/feature")
+	assert.False(t, ok)
+}
+
+func TestRepoBranchPolicy(t *testing.T) {
+	r, _ := newTestRepo(t)
+	testCommit(t, r, "first", time.Now())
+
+	cfg, err := r.Git().Config()
+	require.NoError(t, err)
+	cfg.Raw.Section("calver-branch").Subsection("develop").
+		SetOption("modifier", "dev.").
+		SetOption("autoIncrement", "true")
+	cfg.Raw.Section("calver-branch").Subsection("feature/*").SetOption("allowed", "false")
+	require.NoError(t, r.Git().SetConfig(cfg))
+
+	cv, err := r.Next(CalVerArgs{RawFormat: "YYYY.0M.0D"})
+	require.NoError(t, err)
+	assert.False(t, cv.AutoIncrement)
+
+	cv, err = r.Next(CalVerArgs{RawFormat: "YYYY.0M.0D", Branch: "develop"})
+	require.NoError(t, err)
+	assert.True(t, cv.AutoIncrement)
+	v, err := cv.Version(time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, time.Now().Format("2006.01.02")+"-dev.1", v)
+
+	wt, err := r.Git().Worktree()
+	require.NoError(t, err)
+	require.NoError(t, wt.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("feature/x"), Create: true}))
+	_, err = r.Next(CalVerArgs{RawFormat: "YYYY.0M.0D"})
+	assert.ErrorIs(t, err, ErrBranchNotAllowed)
+	_, err = r.Next(CalVerArgs{RawFormat: "YYYY.0M.0D", IgnoreBranchPolicy: true})
+	assert.NoError(t, err)
+	_, err = r.Next(CalVerArgs{RawFormat: "YYYY.0M.0D", AnyBranch: true})
+	assert.NoError(t, err)
+}
+
+func TestRepoBranchPolicyPreRelease(t *testing.T) {
+	r, _ := newTestRepo(t)
+	cfg, err := r.Git().Config()
+	require.NoError(t, err)
+	cfg.Raw.Section("calver-branch").Subsection("develop").
+		SetOption("modifier", "dev.").
+		SetOption("autoIncrement", "true")
+	require.NoError(t, r.Git().SetConfig(cfg))
+	today := time.Now().Format("2006.01.02")
+
+	tag := func(branch string) (*TagResult, error) {
+		t.Helper()
+		testCommit(t, r, "work on "+branch, time.Now())
+		cv, err := r.Next(CalVerArgs{RawFormat: "YYYY.0M.0D", Branch: branch})
+		require.NoError(t, err)
+		return r.Tag(TagArgs{CV: cv})
+	}
+
+	res, err := tag("develop")
+	require.NoError(t, err)
+	assert.Equal(t, today+"-dev.1", res.Tag)
+
+	// The final release of the day orders above the pre-release made on develop.
+	res, err = tag("main")
+	require.NoError(t, err)
+	assert.Equal(t, today, res.Tag)
+
+	// And develop keeps tagging pre-releases after it.
+	res, err = tag("develop")
+	require.NoError(t, err)
+	assert.Equal(t, today+"-dev.2", res.Tag)
+
+	f, err := NewFormat("YYYY.0M.0D")
+	require.NoError(t, err)
+	latest, err := r.Latest((&CalVer{Format: f}).Regex(), false)
+	require.NoError(t, err)
+	assert.Equal(t, today, latest.Tag())
+}
@@ -0,0 +1,823 @@
+package ver
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+	"github.com/go-git/go-git/v5/storage/filesystem"
+)
+
+var (
+	// ErrNotInRepo is returned when no repository can be found at the given path.
+	ErrNotInRepo = errors.New("no repo found")
+	// ErrFormatNotSet is returned when the repository config has no calver format.
+	ErrFormatNotSet = errors.New("[calver] not set")
+	// ErrNoTags is returned when no tag matches the calver format.
+	ErrNoTags = errors.New("no latest tag found")
+	// ErrTagExists is returned when creating a tag that is already present.
+	ErrTagExists = errors.New("tag already exists")
+	// ErrTagNotFound is returned when deleting or resolving a tag that is not present.
+	ErrTagNotFound = errors.New("tag does not exist")
+	// ErrCommitNotFound is returned when a revision does not resolve to a commit.
+	ErrCommitNotFound = errors.New("commit not found")
+	// ErrTagRejected is returned when origin refuses a tag push because the tag already exists there.
+	ErrTagRejected = errors.New("tag rejected by remote")
+	// ErrNoChanges is returned when tagging with RequireChanges and no commits were made since the latest tag.
+	ErrNoChanges = errors.New("no changes")
+	// ErrVersionNotNewer is returned when a new tag would not order above the latest tag, eg. after clock skew
+	// or a format change.
+	ErrVersionNotNewer = errors.New("version is not newer than the latest tag")
+)
+
+// PushError is returned when git fails to push or fetch tags for a reason other than a rejected tag.
+type PushError struct {
+	Tag    string
+	Output string
+}
+
+func (e *PushError) Error() string {
+	if e.Tag == "" {
+		return fmt.Sprintf("git: %s", e.Output)
+	}
+	return fmt.Sprintf("could not push tag '%s': %s", e.Tag, e.Output)
+}
+
+// TagResult describes a tag created or deleted by a Repo.
+type TagResult struct {
+	// Tag is the name of the tag.
+	Tag string
+	// Hash is the full hash of the commit the tag points to.
+	Hash string
+	// When is the author date of that commit.
+	When time.Time
+	// Pushed is set when the change was pushed to origin.
+	Pushed bool
+	// DryRun is set when the change was only planned, and nothing was written.
+	DryRun bool
+	// Existing is set when the commit was already tagged and no tag was created.
+	Existing bool
+	// Remote and RemoteURL name the remote the change is pushed to, when pushing.
+	Remote    string
+	RemoteURL string
+	// Hooks lists the git hooks that run on push.
+	Hooks []string
+	// Aliases lists the alias tags moved to the tag.
+	Aliases []string
+}
+
+// ShortHash returns the abbreviated commit hash.
+func (t *TagResult) ShortHash() string {
+	if len(t.Hash) < 7 {
+		return t.Hash
+	}
+	return t.Hash[:7]
+}
+
+// Repo is a handle on a repository holding calver tags. It is opened once and reused for every operation,
+// and never writes to stdout, so other tools can embed it.
+type Repo struct {
+	git  *git.Repository
+	tags TagStore
+	// generations caches the generation numbers of commits walked for changelogs.
+	generations map[plumbing.Hash]int
+
+	// FirstParent restricts changelogs to the first-parent history of each release, like git log --first-parent.
+	FirstParent bool
+	// Paths restricts changelogs, describe and RequireChanges to commits touching files under these paths, like
+	// git log -- <path>.
+	Paths []string
+}
+
+// Open opens the repository at path. The path is first opened as-is, which covers bare repositories, and
+// otherwise parent directories are searched for a .git entry. Linked worktrees are supported.
+func Open(path string) (*Repo, error) {
+	if path == "" {
+		path = "."
+	}
+
+	r, err := git.PlainOpenWithOptions(path, &git.PlainOpenOptions{EnableDotGitCommonDir: true})
+	if err != nil && !errors.Is(err, git.ErrRepositoryNotExists) {
+		return nil, fmt.Errorf("could not open repo at %s: %w", path, err)
+	}
+	if err != nil {
+		r, err = git.PlainOpenWithOptions(path, &git.PlainOpenOptions{DetectDotGit: true, EnableDotGitCommonDir: true})
+		if errors.Is(err, git.ErrRepositoryNotExists) {
+			return nil, ErrNotInRepo
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not open repo at %s: %w", path, err)
+		}
+	}
+
+	return NewRepoWithStore(r, &gitStore{repo: r, dir: path}), nil
+}
+
+// NewRepo wraps an already opened repository. Pushes go through the git CLI when the repository is on disk.
+func NewRepo(r *git.Repository) *Repo {
+	s := &gitStore{repo: r}
+	if wt, err := r.Worktree(); err == nil {
+		s.dir = wt.Filesystem.Root()
+	} else if fs, ok := r.Storer.(*filesystem.Storage); ok {
+		s.dir = fs.Filesystem().Root()
+	}
+	return NewRepoWithStore(r, s)
+}
+
+// NewRepoWithStore wraps a repository whose tags are read from and written to the given TagStore.
+func NewRepoWithStore(r *git.Repository, s TagStore) *Repo {
+	return &Repo{git: r, tags: s}
+}
+
+// Git returns the underlying go-git repository.
+func (r *Repo) Git() *git.Repository {
+	return r.git
+}
+
+// Format returns the calver format stored in the repository config, and whether it auto-increments.
+func (r *Repo) Format() (*Format, bool, error) {
+	return r.ComponentFormat("")
+}
+
+// ComponentFormat returns the calver format of a component, stored in a [calver "component"] subsection of the
+// repository config, and whether it auto-increments. Components without a format of their own use [calver].
+func (r *Repo) ComponentFormat(component string) (*Format, bool, error) {
+	conf, err := r.git.Config()
+	if err != nil {
+		return nil, false, fmt.Errorf("could not retrieve config: %w", err)
+	}
+
+	if !conf.Raw.HasSection("calver") {
+		return nil, false, ErrFormatNotSet
+	}
+
+	section := conf.Raw.Section("calver")
+	val := section.Option("format")
+	if component != "" && section.HasSubsection(component) {
+		if v := section.Subsection(component).Option("format"); v != "" {
+			val = v
+		}
+	}
+	if val == "" {
+		return nil, false, fmt.Errorf("[calver].format not set")
+	}
+
+	f, err := NewFormat(val)
+	if err != nil {
+		return nil, false, err
+	}
+	return f, strings.HasSuffix(val, "-AUTO"), nil
+}
+
+// ComponentPaths returns the paths of a component, stored as path options in its [calver "component"]
+// subsection. A component without paths covers the whole repository.
+func (r *Repo) ComponentPaths(component string) ([]string, error) {
+	conf, err := r.git.Config()
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve config: %w", err)
+	}
+	if component == "" || !conf.Raw.HasSection("calver") || !conf.Raw.Section("calver").HasSubsection(component) {
+		return nil, nil
+	}
+	return conf.Raw.Section("calver").Subsection(component).Options.GetAll("path"), nil
+}
+
+// SetFormat stores the calver format in the repository config.
+func (r *Repo) SetFormat(f *Format) error {
+	return r.SetComponentFormat("", f)
+}
+
+// SetComponentFormat stores the calver format of a component in a [calver "component"] subsection.
+func (r *Repo) SetComponentFormat(component string, f *Format) error {
+	conf, err := r.git.Config()
+	if err != nil {
+		return fmt.Errorf("could not retrieve config: %w", err)
+	}
+
+	conf.Raw.SetOption("calver", component, "format", f.String())
+	return r.git.SetConfig(conf)
+}
+
+// List returns up to limit calver tag groups matching reg, newest first.
+func (r *Repo) List(reg *regexp.Regexp, limit int, changelog bool) ([]*CalVerTagGroup, error) {
+	refs, err := r.tags.Tags()
+	if err != nil {
+		return nil, fmt.Errorf("could not find tags: %w", err)
+	}
+
+	tagMap := make(map[string]*CalVerTagGroup)
+	tags := make([]string, 0)
+	for _, tag := range refs {
+		short := tag.Name().Short()
+		if !reg.Match([]byte(short)) {
+			continue
+		}
+		co, _ := r.commitForTag(string(tag.Name()))
+		if co == nil {
+			continue
+		}
+
+		hash := co.Hash.String()[:7]
+		if tagMap[short] == nil {
+			tags = append(tags, short)
+			tagMap[short] = &CalVerTagGroup{
+				Hash:      hash,
+				Commit:    co,
+				When:      co.Author.When,
+				Tags:      []string{short},
+				Refs:      []*plumbing.Reference{tag},
+				ChangeLog: []*object.Commit{co},
+			}
+			continue
+		}
+
+		tagMap[short].Tags = append(tagMap[short].Tags, short)
+		tagMap[short].Refs = append(tagMap[short].Refs, tag)
+	}
+
+	sortTags(tags)
+
+	if changelog {
+		for i, tag := range tags {
+			if i >= limit {
+				break
+			}
+			var prev *object.Commit
+			if i < len(tags)-1 {
+				prev = tagMap[tags[i+1]].Commit
+			}
+
+			logs, err := r.changelog(prev, tagMap[tag].Commit)
+			if err != nil {
+				return nil, err
+			}
+			tagMap[tag].ChangeLog = logs
+		}
+	}
+
+	return collectGroups(tagMap, tags, limit), nil
+}
+
+// Latest returns the newest calver tag group matching reg.
+func (r *Repo) Latest(reg *regexp.Regexp, changelog bool) (*CalVerTagGroup, error) {
+	latestList, err := r.List(reg, 1, changelog)
+	if err != nil {
+		return nil, err
+	}
+	for _, tagGroup := range latestList {
+		return tagGroup, nil
+	}
+
+	return nil, ErrNoTags
+}
+
+// NextAutoInc returns the auto-increment the next tag for cv should carry.
+func (r *Repo) NextAutoInc(cv *CalVer) (int, error) {
+	// Get all tags that match the format, not just the latest group
+	allTags, err := r.List(cv.Regex(), 100, false)
+	if err != nil {
+		return 1, nil // If no tags found, start at 1
+	}
+
+	return nextAutoInc(cv, allTags)
+}
+
+// Next builds the CalVer for the next release, applying the branch policy and resolving the auto-increment
+// against this repository.
+func (r *Repo) Next(a CalVerArgs) (*CalVer, error) {
+	if err := r.applyBranchPolicy(&a); err != nil {
+		return nil, err
+	}
+	return nextCalVer(a, r.NextAutoInc)
+}
+
+// ResolveCommit resolves a revision (hash, abbreviated hash, branch, tag or HEAD) to a commit.
+// An empty revision resolves to HEAD.
+func (r *Repo) ResolveCommit(rev string) (*object.Commit, error) {
+	if rev == "" {
+		rev = "HEAD"
+	}
+	h, err := r.git.ResolveRevision(plumbing.Revision(rev))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrCommitNotFound, rev)
+	}
+	co, err := r.git.CommitObject(*h)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrCommitNotFound, rev)
+	}
+	return co, nil
+}
+
+// TagExists reports whether the repository has a tag with the given name.
+func (r *Repo) TagExists(tag string) bool {
+	refs, err := r.tags.Tags()
+	if err != nil {
+		return false
+	}
+	for _, ref := range refs {
+		if ref.Name().Short() == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// TagsAt returns the tags matching reg that point at the commit given by rev, newest first.
+func (r *Repo) TagsAt(reg *regexp.Regexp, rev string) ([]string, error) {
+	co, err := r.ResolveCommit(rev)
+	if err != nil {
+		return nil, err
+	}
+	refs, err := r.tags.Tags()
+	if err != nil {
+		return nil, fmt.Errorf("could not find tags: %w", err)
+	}
+
+	tags := make([]string, 0)
+	for _, ref := range refs {
+		short := ref.Name().Short()
+		if !reg.MatchString(short) {
+			continue
+		}
+		if c, err := r.commitForTag(string(ref.Name())); err == nil && c.Hash == co.Hash {
+			tags = append(tags, short)
+		}
+	}
+	sortTags(tags)
+	return tags, nil
+}
+
+// Contains returns the calver tag groups matching reg whose commit has rev in its history, oldest first. The
+// first group is the release that first shipped rev. Ancestry is checked on the commit graph, so releases cut
+// from other branches are left out even when they are newer.
+func (r *Repo) Contains(reg *regexp.Regexp, rev string) ([]*CalVerTagGroup, error) {
+	co, err := r.ResolveCommit(rev)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := r.List(reg, math.MaxInt, false)
+	if err != nil {
+		return nil, err
+	}
+
+	contains := make([]*CalVerTagGroup, 0)
+	for i := len(groups) - 1; i >= 0; i-- {
+		g := groups[i]
+		if g.Commit.Hash != co.Hash {
+			ok, err := co.IsAncestor(g.Commit)
+			if err != nil {
+				return nil, fmt.Errorf("could not walk history of %s: %w", g.Tag(), err)
+			}
+			if !ok {
+				continue
+			}
+		}
+		contains = append(contains, g)
+	}
+	return contains, nil
+}
+
+// Tag creates a tag on the commit given by args.Hash (HEAD when empty), named args.Tag or, when that is empty,
+// the version computed from args.CV, which is a patch version on release branches (see NextVersion). The tag is
+// pushed to origin when args.Push is set. With args.DryRun set, the tag is validated and described but not
+// created or pushed.
+func (r *Repo) Tag(args TagArgs) (*TagResult, error) {
+	return r.tag(args, false)
+}
+
+func (r *Repo) tag(args TagArgs, replacing bool) (*TagResult, error) {
+	co, err := r.ResolveCommit(args.Hash)
+	if err != nil {
+		return nil, err
+	}
+	if existing, err := r.released(args, co); existing != nil || err != nil {
+		return existing, err
+	}
+
+	v, patch := args.Tag, false
+	if v == "" {
+		v, patch, err = r.NextVersion(args.CV)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !replacing && !args.Force && !patch {
+		if err := r.checkNewer(args.CV, v); err != nil {
+			return nil, err
+		}
+	}
+
+	res := &TagResult{Tag: v, Hash: co.Hash.String(), When: co.Author.When, DryRun: args.DryRun}
+	if args.Push {
+		r.describePush(res)
+	}
+	if args.DryRun {
+		if !replacing && r.TagExists(v) {
+			return nil, fmt.Errorf("%w: %s", ErrTagExists, v)
+		}
+		if args.Aliases && !replacing {
+			res.Aliases, err = r.moveAliases(args, v, co, false)
+		}
+		return res, err
+	}
+
+	if err := r.createTag(v, co); err != nil {
+		return nil, err
+	}
+	if args.Push {
+		if err := r.tags.Push(v, false); err != nil {
+			return res, err
+		}
+		res.Pushed = true
+	}
+	if args.Aliases && !replacing {
+		res.Aliases, err = r.moveAliases(args, v, co, false)
+	}
+	return res, err
+}
+
+// TagAndPush creates the next tag and pushes it to origin as a single step. When origin rejects the push because
+// another job created the same tag first, tags are re-fetched, the auto-increment is recomputed and the push is
+// retried up to retries times. The result holds the tag that actually landed on origin. A dry run plans a single
+// attempt without contacting origin.
+func (r *Repo) TagAndPush(args TagArgs, retries int) (*TagResult, error) {
+	args.Push = true
+	if args.DryRun {
+		return r.Tag(args)
+	}
+
+	co, err := r.ResolveCommit(args.Hash)
+	if err != nil {
+		return nil, err
+	}
+	if existing, err := r.released(args, co); existing != nil || err != nil {
+		return existing, err
+	}
+
+	for attempt := 0; ; attempt++ {
+		v, patch := args.Tag, false
+		if v == "" {
+			v, patch, err = r.NextVersion(args.CV)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if !args.Force && !patch {
+			if err := r.checkNewer(args.CV, v); err != nil {
+				return nil, err
+			}
+		}
+
+		err = r.createTag(v, co)
+		if err != nil && !errors.Is(err, ErrTagExists) {
+			return nil, err
+		}
+		if err == nil {
+			err = r.tags.Push(v, false)
+			if err == nil {
+				res := &TagResult{Tag: v, Hash: co.Hash.String(), When: co.Author.When, Pushed: true}
+				r.describePush(res)
+				if args.Aliases {
+					res.Aliases, err = r.moveAliases(args, v, co, false)
+				}
+				return res, err
+			}
+			if !errors.Is(err, ErrTagRejected) {
+				return nil, err
+			}
+			if err := r.tags.DeleteTag(v); err != nil {
+				return nil, fmt.Errorf("could not delete rejected tag '%s': %w", v, err)
+			}
+		}
+
+		if args.Tag != "" || (!args.CV.AutoIncrement && !patch) {
+			return nil, fmt.Errorf("tag '%s': %w", v, ErrTagRejected)
+		}
+		if attempt >= retries {
+			return nil, fmt.Errorf("tag '%s' after %d attempts: %w", v, attempt+1, ErrTagRejected)
+		}
+
+		if err := r.tags.Fetch(); err != nil {
+			return nil, err
+		}
+		if patch {
+			continue
+		}
+		inc, err := r.NextAutoInc(args.CV)
+		if err != nil {
+			return nil, err
+		}
+		args.CV.Increment = uint(inc)
+	}
+}
+
+// Untag deletes args.Tag, and removes it from origin when args.Push is set. With args.DryRun set, the tag is
+// only looked up.
+func (r *Repo) Untag(args TagArgs) (*TagResult, error) {
+	co, err := r.commitForTag(plumbing.NewTagReferenceName(args.Tag).String())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrTagNotFound, args.Tag)
+	}
+
+	res := &TagResult{Tag: args.Tag, Hash: co.Hash.String(), When: co.Author.When, DryRun: args.DryRun}
+	if args.Push {
+		r.describePush(res)
+	}
+	if args.DryRun {
+		return res, nil
+	}
+
+	err = r.tags.DeleteTag(args.Tag)
+	if err != nil {
+		return nil, fmt.Errorf("could not delete tag '%s': %w", args.Tag, err)
+	}
+
+	if args.Push {
+		if err := r.tags.Push(args.Tag, true); err != nil {
+			return res, err
+		}
+		res.Pushed = true
+	}
+	return res, nil
+}
+
+// Retag moves args.Tag to the commit given by args.Hash. It returns the removed and the recreated tag. The
+// moved tag keeps its name, so it is not checked against newer tags, and it is validated before the old tag is
+// deleted so a failed retag leaves the tag in place.
+func (r *Repo) Retag(args TagArgs) (*TagResult, *TagResult, error) {
+	if !args.DryRun {
+		check := args
+		check.DryRun, check.Push = true, false
+		if _, err := r.tag(check, true); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	old, err := r.Untag(args)
+	if err != nil {
+		return old, nil, err
+	}
+
+	res, err := r.tag(args, true)
+	return old, res, err
+}
+
+// Changelog returns the commits reachable from the to revision but not from the from revision, newest first,
+// like git log from..to. An empty from lists the whole history of to.
+func (r *Repo) Changelog(from, to string) ([]*object.Commit, error) {
+	toCommit, err := r.ResolveCommit(to)
+	if err != nil {
+		return nil, err
+	}
+	var fromCommit *object.Commit
+	if from != "" {
+		fromCommit, err = r.ResolveCommit(from)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return r.changelog(fromCommit, toCommit)
+}
+
+func (r *Repo) changelog(from, to *object.Commit) ([]*object.Commit, error) {
+	logs, err := r.between(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.FirstParent {
+		included := make(map[plumbing.Hash]bool, len(logs))
+		for _, c := range logs {
+			included[c.Hash] = true
+		}
+		logs = logs[:0]
+		for c := to; c != nil && included[c.Hash]; {
+			logs = append(logs, c)
+			if c.NumParents() == 0 {
+				break
+			}
+			next, err := c.Parent(0)
+			if err != nil {
+				return nil, fmt.Errorf("could not walk history of %s: %w", c.Hash, err)
+			}
+			c = next
+		}
+	}
+
+	if len(r.Paths) > 0 {
+		touching := logs[:0]
+		for _, c := range logs {
+			ok, err := r.touches(c)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				touching = append(touching, c)
+			}
+		}
+		logs = touching
+	}
+
+	sort.SliceStable(logs, func(i, j int) bool {
+		return logs[i].Committer.When.After(logs[j].Committer.When)
+	})
+	return logs, nil
+}
+
+// touches reports whether c changes anything under Paths. Like git log -- <path>, a commit that leaves the
+// paths identical to one of its parents, such as a merge bringing nothing new to them, does not count.
+func (r *Repo) touches(c *object.Commit) (bool, error) {
+	tree, err := c.Tree()
+	if err != nil {
+		return false, fmt.Errorf("could not read tree of %s: %w", c.Hash, err)
+	}
+	if c.NumParents() == 0 {
+		for _, p := range r.Paths {
+			if pathHash(tree, p) != plumbing.ZeroHash {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	same := false
+	err = c.Parents().ForEach(func(parent *object.Commit) error {
+		parentTree, err := parent.Tree()
+		if err != nil {
+			return fmt.Errorf("could not read tree of %s: %w", parent.Hash, err)
+		}
+		for _, p := range r.Paths {
+			if pathHash(tree, p) != pathHash(parentTree, p) {
+				return nil
+			}
+		}
+		same = true
+		return storer.ErrStop
+	})
+	if err != nil {
+		return false, err
+	}
+	return !same, nil
+}
+
+// pathHash returns the hash of the file or directory at p in tree, or the zero hash when there is none.
+func pathHash(tree *object.Tree, p string) plumbing.Hash {
+	p = strings.Trim(path.Clean("/"+p), "/")
+	if p == "" {
+		return tree.Hash
+	}
+	e, err := tree.FindEntry(p)
+	if err != nil {
+		return plumbing.ZeroHash
+	}
+	return e.Hash
+}
+
+// released applies args.IfUntagged and args.RequireChanges to co. It returns the tag already on co when there
+// is one to reuse, or ErrNoChanges when co has nothing new since the latest tag.
+func (r *Repo) released(args TagArgs, co *object.Commit) (*TagResult, error) {
+	if !args.IfUntagged && !args.RequireChanges {
+		return nil, nil
+	}
+	reg := r.ReleaseRegex(args.CV)
+
+	if args.IfUntagged {
+		tags, err := r.TagsAt(reg, co.Hash.String())
+		if err != nil {
+			return nil, err
+		}
+		if len(tags) > 0 {
+			return &TagResult{Tag: tags[0], Hash: co.Hash.String(), When: co.Author.When, DryRun: args.DryRun, Existing: true}, nil
+		}
+	}
+
+	if args.RequireChanges {
+		if err := r.RequireChanges(reg, co.Hash.String()); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+// RequireChanges returns ErrNoChanges when there are no commits between the latest tag matching reg and rev
+// (HEAD when empty). With Paths set, only commits touching them count.
+func (r *Repo) RequireChanges(reg *regexp.Regexp, rev string) error {
+	co, err := r.ResolveCommit(rev)
+	if err != nil {
+		return err
+	}
+	latest, err := r.Latest(reg, false)
+	if errors.Is(err, ErrNoTags) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	logs, err := r.changelog(latest.Commit, co)
+	if err != nil {
+		return err
+	}
+	if len(logs) == 0 {
+		return fmt.Errorf("%w since %s", ErrNoChanges, latest.Tag())
+	}
+	return nil
+}
+
+// checkNewer returns ErrVersionNotNewer when v orders below the latest tag of cv's format with as many segments
+// and the same modifier stream, so patch tags made on release branches do not hold back the main line, and
+// pre-releases such as dev.N tagged by a branch policy neither block nor are blocked by final releases. A
+// version equal to the latest tag is left to the existing tag checks.
+func (r *Repo) checkNewer(cv *CalVer, v string) error {
+	if cv == nil {
+		return nil
+	}
+	segments, modifier, err := ParseVersion(v)
+	if err != nil {
+		return nil
+	}
+	groups, err := r.List((&CalVer{Component: cv.Component, Format: cv.Format}).Regex(), math.MaxInt, false)
+	if err != nil {
+		return err
+	}
+
+	for _, g := range groups {
+		s, m, err := ParseVersion(g.Tag())
+		if err != nil || len(s) != len(segments) || modifierStream(m) != modifierStream(modifier) {
+			continue
+		}
+		c := CompareVersions(v, g.Tag())
+		if c < 0 || (c == 0 && v != g.Tag()) {
+			return fmt.Errorf("%w: %s is not greater than %s", ErrVersionNotNewer, v, g.Tag())
+		}
+		return nil
+	}
+	return nil
+}
+
+// describePush records the remote a tag is pushed to and the hooks git runs while pushing it.
+func (r *Repo) describePush(res *TagResult) {
+	res.Remote = "origin"
+	if remote, err := r.git.Remote(res.Remote); err == nil && len(remote.Config().URLs) > 0 {
+		res.RemoteURL = remote.Config().URLs[0]
+	}
+	if r.hookEnabled("pre-push") {
+		res.Hooks = append(res.Hooks, "pre-push")
+	}
+}
+
+// hookEnabled reports whether an executable hook with the given name is installed, honouring core.hooksPath.
+func (r *Repo) hookEnabled(name string) bool {
+	if conf, err := r.git.ConfigScoped(config.SystemScope); err == nil {
+		if dir := conf.Raw.Section("core").Option("hooksPath"); dir != "" {
+			if !filepath.IsAbs(dir) {
+				wt, err := r.git.Worktree()
+				if err != nil {
+					return false
+				}
+				dir = filepath.Join(wt.Filesystem.Root(), dir)
+			}
+			fi, err := os.Stat(filepath.Join(dir, name))
+			return err == nil && !fi.IsDir() && fi.Mode()&0111 != 0
+		}
+	}
+
+	fs, ok := r.git.Storer.(*filesystem.Storage)
+	if !ok {
+		return false
+	}
+	fi, err := fs.Filesystem().Stat(path.Join("hooks", name))
+	return err == nil && !fi.IsDir() && fi.Mode()&0111 != 0
+}
+
+func (r *Repo) commitForTag(tag string) (*object.Commit, error) {
+	rev, err := r.git.ResolveRevision(plumbing.Revision(tag))
+	if err != nil {
+		return nil, err
+	}
+	return r.git.CommitObject(*rev)
+}
+
+func (r *Repo) createTag(tag string, co *object.Commit) error {
+	if r.TagExists(tag) {
+		return fmt.Errorf("%w: %s", ErrTagExists, tag)
+	}
+	err := r.tags.CreateTag(tag, co.Hash)
+	if err != nil {
+		return fmt.Errorf("could not create tag '%s': %w", tag, err)
+	}
+	return nil
+}
@@ -0,0 +1,452 @@
+package ver
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-billy/v5/util"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRepo(t *testing.T) (*Repo, *MemoryStore) {
+	t.Helper()
+	r, s, err := NewMemoryRepo()
+	require.NoError(t, err)
+	return r, s
+}
+
+func testCommit(t *testing.T, r *Repo, msg string, when time.Time, parents ...plumbing.Hash) plumbing.Hash {
+	t.Helper()
+	wt, err := r.Git().Worktree()
+	require.NoError(t, err)
+	sig := &object.Signature{Name: "dev", Email: "dev@example.com", When: when}
+	h, err := wt.Commit(msg, &git.CommitOptions{
+		AllowEmptyCommits: true,
+		Author:            sig,
+		Committer:         sig,
+		Parents:           parents,
+	})
+	require.NoError(t, err)
+	return h
+}
+
+func testTag(t *testing.T, s TagStore, name string, h plumbing.Hash) {
+	t.Helper()
+	require.NoError(t, s.CreateTag(name, h))
+}
+
+func TestRepoList(t *testing.T) {
+	r, s := newTestRepo(t)
+	day := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	c1 := testCommit(t, r, "first", day)
+	c2 := testCommit(t, r, "second", day.Add(time.Hour))
+	c3 := testCommit(t, r, "third", day.Add(24*time.Hour))
+	testTag(t, s, "2024.01.01", c1)
+	testTag(t, s, "2024.01.02-1", c2)
+	testTag(t, s, "2024.01.02-2", c3)
+	testTag(t, s, "v1.0.0", c3)
+
+	cv, err := NewCalVer(CalVerArgs{RawFormat: "YYYY.0M.0D"})
+	require.NoError(t, err)
+
+	groups, err := r.List(cv.Regex(), 10, false)
+	require.NoError(t, err)
+	tags := make([]string, 0)
+	for _, g := range groups {
+		tags = append(tags, g.Tags...)
+	}
+	assert.Equal(t, []string{"2024.01.02-2", "2024.01.02-1", "2024.01.01"}, tags)
+	assert.True(t, groups[0].Latest)
+	assert.Equal(t, c3.String()[:7], groups[0].Hash)
+
+	groups, err = r.List(cv.Regex(), 1, false)
+	require.NoError(t, err)
+	assert.Len(t, groups, 1)
+
+	latest, err := r.Latest(cv.Regex(), false)
+	require.NoError(t, err)
+	assert.Equal(t, "2024.01.02-2", latest.LatestTag)
+}
+
+func TestRepoLatestNoTags(t *testing.T) {
+	r, _ := newTestRepo(t)
+	testCommit(t, r, "first", time.Now())
+
+	cv, err := NewCalVer(CalVerArgs{RawFormat: "YYYY.0M.0D"})
+	require.NoError(t, err)
+
+	_, err = r.Latest(cv.Regex(), false)
+	assert.ErrorIs(t, err, ErrNoTags)
+}
+
+func TestRepoChangelog(t *testing.T) {
+	r, s := newTestRepo(t)
+	day := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	c1 := testCommit(t, r, "first", day)
+	testTag(t, s, "2024.01.01", c1)
+	feature := testCommit(t, r, "feature", day.Add(time.Hour), c1)
+	// Authored long before the previous release, as a rebased or cherry-picked commit would be.
+	c2 := testCommit(t, r, "fix", day.Add(-48*time.Hour), c1)
+	merge := testCommit(t, r, "merge feature", day.Add(24*time.Hour), c2, feature)
+	testTag(t, s, "2024.01.02", merge)
+	testCommit(t, r, "unreleased", day.Add(48*time.Hour), merge)
+
+	cv, err := NewCalVer(CalVerArgs{RawFormat: "YYYY.0M.0D"})
+	require.NoError(t, err)
+
+	messages := func(groups []*CalVerTagGroup) [][]string {
+		out := make([][]string, 0)
+		for _, g := range groups {
+			msgs := make([]string, 0)
+			for _, c := range g.ChangeLog {
+				msgs = append(msgs, c.Message)
+			}
+			out = append(out, msgs)
+		}
+		return out
+	}
+
+	groups, err := r.List(cv.Regex(), 10, true)
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"merge feature", "feature", "fix"}, {"first"}}, messages(groups))
+
+	r.FirstParent = true
+	groups, err = r.List(cv.Regex(), 10, true)
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"merge feature", "fix"}, {"first"}}, messages(groups))
+
+	logs, err := r.Changelog("2024.01.02", "HEAD")
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	assert.Equal(t, "unreleased", logs[0].Message)
+}
+
+func TestRepoChangelogRange(t *testing.T) {
+	r, _ := newTestRepo(t)
+	day := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	trunk := make([]plumbing.Hash, 0, 20)
+	parent := testCommit(t, r, "root", day)
+	for i := 1; i <= 20; i++ {
+		parent = testCommit(t, r, "main", day.Add(time.Duration(i)*time.Hour), parent)
+		trunk = append(trunk, parent)
+	}
+	side := testCommit(t, r, "side", day.Add(25*time.Hour), trunk[9])
+	// Committed with a date older than most of main, so a walk by date alone would stop too early.
+	skewed := testCommit(t, r, "skewed", day.Add(5*time.Hour), side)
+	m1 := testCommit(t, r, "main after", day.Add(21*time.Hour), trunk[19])
+	merge := testCommit(t, r, "merge", day.Add(30*time.Hour), m1, skewed)
+
+	count := func(from, to plumbing.Hash) int {
+		t.Helper()
+		logs, err := r.Changelog(from.String(), to.String())
+		require.NoError(t, err)
+		return len(logs)
+	}
+	// Counts match git rev-list --count from..to.
+	assert.Equal(t, 4, count(trunk[19], merge))
+	assert.Equal(t, 12, count(skewed, merge))
+	assert.Equal(t, 2, count(trunk[9], skewed))
+	assert.Equal(t, 0, count(merge, trunk[19]))
+
+	// The previous release reaches the shared history through commits dated days before it, so a walk by date
+	// would reach the shared commits from the new release first and keep them.
+	base := testCommit(t, r, "base", day.Add(20*time.Hour))
+	shared := testCommit(t, r, "shared", day.Add(30*time.Hour), base)
+	old := shared
+	for i := 0; i < 8; i++ {
+		old = testCommit(t, r, "old", day.Add(-100*time.Hour), old)
+	}
+	prev := testCommit(t, r, "previous release", day.Add(50*time.Hour), old)
+	next := testCommit(t, r, "next release", day.Add(40*time.Hour), shared)
+	logs, err := r.Changelog(prev.String(), next.String())
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	assert.Equal(t, next, logs[0].Hash)
+}
+
+func TestRepoNextAutoInc(t *testing.T) {
+	today := time.Now()
+	tests := []struct {
+		name     string
+		modifier string
+		tags     []string
+		want     int
+	}{
+		{name: "no tags", tags: nil, want: 1},
+		{name: "older release only", tags: []string{"2001.01.01-4"}, want: 1},
+		{name: "increments today", tags: []string{"{today}-1", "{today}-2", "{today}-10"}, want: 11},
+		{name: "modifier", modifier: "RC", tags: []string{"{today}-RC3", "{today}-7"}, want: 4},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r, s := newTestRepo(t)
+			h := testCommit(t, r, "first", today)
+
+			cv, err := NewCalVer(CalVerArgs{RawFormat: "YYYY.0M.0D", Modifier: test.modifier, AutoIncrement: true})
+			require.NoError(t, err)
+			for _, tag := range test.tags {
+				testTag(t, s, strings.ReplaceAll(tag, "{today}", cv.Format.Version(today)), h)
+			}
+
+			inc, err := r.NextAutoInc(cv)
+			require.NoError(t, err)
+			assert.Equal(t, test.want, inc)
+		})
+	}
+}
+
+func TestRepoComponents(t *testing.T) {
+	r, s := newTestRepo(t)
+	f, err := NewFormat("YYYY.0M.0D")
+	require.NoError(t, err)
+	require.NoError(t, r.SetFormat(f))
+	apiFormat, err := NewFormat("YYYY.0M")
+	require.NoError(t, err)
+	require.NoError(t, r.SetComponentFormat("api", apiFormat))
+
+	got, _, err := r.ComponentFormat("api")
+	require.NoError(t, err)
+	assert.Equal(t, "YYYY.0M", got.String())
+	got, _, err = r.ComponentFormat("web")
+	require.NoError(t, err)
+	assert.Equal(t, "YYYY.0M.0D", got.String())
+
+	c1 := testCommit(t, r, "first", time.Now())
+	month := time.Now().Format("2006.01")
+	testTag(t, s, "api/"+month+"-1", c1)
+	testTag(t, s, "api/"+month+"-2", c1)
+	testTag(t, s, "web/"+time.Now().Format("2006.01.02")+"-5", c1)
+
+	cv, err := r.Next(CalVerArgs{Format: apiFormat, Component: "api", AutoIncrement: true})
+	require.NoError(t, err)
+	v, err := cv.Version(time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, "api/"+month+"-3", v)
+
+	cv, err = r.Next(CalVerArgs{Format: f, AutoIncrement: true})
+	require.NoError(t, err)
+	assert.Equal(t, uint(1), cv.Increment)
+}
+
+func TestRepoPaths(t *testing.T) {
+	r, s := newTestRepo(t)
+	wt, err := r.Git().Worktree()
+	require.NoError(t, err)
+	reg := regexp.MustCompile(`^api/`)
+	commitFile := func(name, content, msg string) plumbing.Hash {
+		require.NoError(t, util.WriteFile(wt.Filesystem, name, []byte(content), 0644))
+		_, err := wt.Add(name)
+		require.NoError(t, err)
+		return testCommit(t, r, msg, time.Now())
+	}
+
+	testTag(t, s, "api/2024.01.01", commitFile("api/main.go", "a", "api"))
+	commitFile("web/index.html", "w", "web")
+	r.Paths = []string{"api/"}
+	assert.ErrorIs(t, r.RequireChanges(reg, ""), ErrNoChanges)
+
+	commitFile("api/main.go", "b", "api fix")
+	assert.NoError(t, r.RequireChanges(reg, ""))
+	logs, err := r.Changelog("api/2024.01.01", "HEAD")
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	assert.Equal(t, "api fix", logs[0].Message)
+
+	cfg, err := r.Git().Config()
+	require.NoError(t, err)
+	cfg.Raw.Section("calver").Subsection("api").AddOption("path", "api")
+	require.NoError(t, r.Git().SetConfig(cfg))
+	p, err := r.ComponentPaths("api")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"api"}, p)
+}
+
+func TestRepoRetag(t *testing.T) {
+	r, s := newTestRepo(t)
+	c1 := testCommit(t, r, "first", time.Now())
+	c2 := testCommit(t, r, "second", time.Now())
+	testTag(t, s, "2024.01.01", c1)
+	require.NoError(t, s.Push("2024.01.01", false))
+
+	old, res, err := r.Retag(TagArgs{Tag: "2024.01.01", Hash: c2.String(), Push: true})
+	require.NoError(t, err)
+	assert.Equal(t, c1.String(), old.Hash)
+	assert.Equal(t, c2.String(), res.Hash)
+	assert.True(t, res.Pushed)
+
+	co, err := r.commitForTag("2024.01.01")
+	require.NoError(t, err)
+	assert.Equal(t, c2, co.Hash)
+
+	remote, err := s.Origin.Reference(plumbing.NewTagReferenceName("2024.01.01"))
+	require.NoError(t, err)
+	assert.Equal(t, c2, remote.Hash())
+
+	// An older release moves even though newer tags exist.
+	f, err := NewFormat("YYYY.0M.0D")
+	require.NoError(t, err)
+	c3 := testCommit(t, r, "third", time.Now())
+	testTag(t, s, "2024.02.01", c2)
+	testTag(t, s, "2024.03.01", c3)
+	_, res, err = r.Retag(TagArgs{CV: &CalVer{Format: f}, Tag: "2024.01.01", Hash: c1.String(), Push: true})
+	require.NoError(t, err)
+	assert.Equal(t, c1.String(), res.Hash)
+	co, err = r.commitForTag("2024.01.01")
+	require.NoError(t, err)
+	assert.Equal(t, c1, co.Hash)
+
+	// A retag that cannot be created leaves the old tag in place.
+	_, _, err = r.Retag(TagArgs{CV: &CalVer{Format: f}, Tag: "2024.01.01", Hash: "unknown"})
+	assert.Error(t, err)
+	co, err = r.commitForTag("2024.01.01")
+	require.NoError(t, err)
+	assert.Equal(t, c1, co.Hash)
+}
+
+func TestRepoDryRun(t *testing.T) {
+	r, s := newTestRepo(t)
+	c1 := testCommit(t, r, "first", time.Now())
+	c2 := testCommit(t, r, "second", time.Now())
+	testTag(t, s, "2024.01.01", c1)
+
+	res, err := r.Tag(TagArgs{Tag: "2024.01.02", Push: true, DryRun: true})
+	require.NoError(t, err)
+	assert.True(t, res.DryRun)
+	assert.False(t, res.Pushed)
+	assert.Equal(t, c2.String(), res.Hash)
+	assert.Equal(t, "origin", res.Remote)
+	assert.False(t, r.TagExists("2024.01.02"))
+
+	_, err = r.Tag(TagArgs{Tag: "2024.01.01", DryRun: true})
+	assert.ErrorIs(t, err, ErrTagExists)
+
+	old, res, err := r.Retag(TagArgs{Tag: "2024.01.01", Hash: c2.String(), Push: true, DryRun: true})
+	require.NoError(t, err)
+	assert.Equal(t, c1.String(), old.Hash)
+	assert.Equal(t, c2.String(), res.Hash)
+
+	co, err := r.commitForTag("2024.01.01")
+	require.NoError(t, err)
+	assert.Equal(t, c1, co.Hash)
+	_, err = s.Origin.Reference(plumbing.NewTagReferenceName("2024.01.01"))
+	assert.Error(t, err)
+}
+
+func TestRepoTagIfUntagged(t *testing.T) {
+	r, _ := newTestRepo(t)
+	head := testCommit(t, r, "first", time.Now())
+
+	cv, err := r.Next(CalVerArgs{RawFormat: "YYYY.0M.0D", AutoIncrement: true})
+	require.NoError(t, err)
+	first, err := r.Tag(TagArgs{CV: cv, IfUntagged: true})
+	require.NoError(t, err)
+	assert.False(t, first.Existing)
+
+	cv, err = r.Next(CalVerArgs{RawFormat: "YYYY.0M.0D", AutoIncrement: true})
+	require.NoError(t, err)
+	again, err := r.Tag(TagArgs{CV: cv, IfUntagged: true})
+	require.NoError(t, err)
+	assert.True(t, again.Existing)
+	assert.Equal(t, first.Tag, again.Tag)
+	assert.Equal(t, ActionExisting, again.Info(ActionCreated).Action)
+
+	tags, err := r.TagsAt(cv.Regex(), head.String())
+	require.NoError(t, err)
+	assert.Equal(t, []string{first.Tag}, tags)
+}
+
+func TestRepoTagRequireChanges(t *testing.T) {
+	r, s := newTestRepo(t)
+	c1 := testCommit(t, r, "first", time.Now())
+	testTag(t, s, "2024.01.01", c1)
+
+	cv, err := r.Next(CalVerArgs{RawFormat: "YYYY.0M.0D"})
+	require.NoError(t, err)
+	_, err = r.Tag(TagArgs{CV: cv, RequireChanges: true})
+	assert.ErrorIs(t, err, ErrNoChanges)
+
+	testCommit(t, r, "second", time.Now())
+	_, err = r.Tag(TagArgs{CV: cv, RequireChanges: true})
+	assert.NoError(t, err)
+}
+
+func TestRepoTagNotNewer(t *testing.T) {
+	r, s := newTestRepo(t)
+	c1 := testCommit(t, r, "first", time.Now())
+	testTag(t, s, "2099.12.31", c1)
+	testCommit(t, r, "second", time.Now())
+
+	cv, err := r.Next(CalVerArgs{RawFormat: "YYYY.0M.0D"})
+	require.NoError(t, err)
+	_, err = r.Tag(TagArgs{CV: cv})
+	assert.ErrorIs(t, err, ErrVersionNotNewer)
+
+	res, err := r.Tag(TagArgs{CV: cv, Force: true})
+	require.NoError(t, err)
+	assert.True(t, r.TagExists(res.Tag))
+}
+
+func TestRepoContains(t *testing.T) {
+	r, s := newTestRepo(t)
+	reg := regexp.MustCompile(`^2024\.\d{2}\.\d{2}$`)
+	day := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	c1 := testCommit(t, r, "first", day)
+	testTag(t, s, "2024.01.01", c1)
+	fix := testCommit(t, r, "fix", day.Add(time.Hour), c1)
+	c3 := testCommit(t, r, "third", day.Add(2*time.Hour), fix)
+	testTag(t, s, "2024.01.02", c3)
+	side := testCommit(t, r, "side", day.Add(3*time.Hour), c1)
+	testTag(t, s, "2024.01.03", side)
+	c5 := testCommit(t, r, "fifth", day.Add(4*time.Hour), c3)
+	testTag(t, s, "2024.01.10", c5)
+
+	groups, err := r.Contains(reg, fix.String())
+	require.NoError(t, err)
+	tags := make([]string, 0)
+	for _, g := range groups {
+		tags = append(tags, g.Tag())
+	}
+	assert.Equal(t, []string{"2024.01.02", "2024.01.10"}, tags)
+
+	groups, err = r.Contains(reg, c1.String())
+	require.NoError(t, err)
+	assert.Len(t, groups, 4)
+	assert.Equal(t, "2024.01.01", groups[0].Tag())
+}
+
+func TestRepoRetagMissing(t *testing.T) {
+	r, _ := newTestRepo(t)
+	testCommit(t, r, "first", time.Now())
+
+	_, _, err := r.Retag(TagArgs{Tag: "2024.01.01"})
+	assert.ErrorIs(t, err, ErrTagNotFound)
+}
+
+func TestRepoTagAndPushRetries(t *testing.T) {
+	r, s := newTestRepo(t)
+	other := testCommit(t, r, "other job", time.Now())
+	head := testCommit(t, r, "this job", time.Now())
+
+	cv, err := r.Next(CalVerArgs{RawFormat: "YYYY.0M.0D", AutoIncrement: true})
+	require.NoError(t, err)
+	first, err := cv.Version(time.Now())
+	require.NoError(t, err)
+
+	// Another job already pushed the tag this one is about to create.
+	require.NoError(t, s.Origin.SetReference(plumbing.NewHashReference(plumbing.NewTagReferenceName(first), other)))
+
+	res, err := r.TagAndPush(TagArgs{CV: cv}, 3)
+	require.NoError(t, err)
+	assert.Equal(t, cv.Format.Version(time.Now())+"-2", res.Tag)
+	assert.Equal(t, head.String(), res.Hash)
+
+	_, err = r.TagAndPush(TagArgs{CV: cv, Tag: res.Tag, Hash: other.String()}, 3)
+	assert.ErrorIs(t, err, ErrTagRejected)
+}
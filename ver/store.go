@@ -0,0 +1,211 @@
+package ver
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+// TagStore is where a Repo reads and writes tags, and how tag changes reach origin.
+type TagStore interface {
+	// Tags returns every tag reference.
+	Tags() ([]*plumbing.Reference, error)
+	// CreateTag creates a lightweight tag pointing at hash.
+	CreateTag(name string, hash plumbing.Hash) error
+	// DeleteTag removes a local tag.
+	DeleteTag(name string) error
+	// Push publishes a tag to origin, or deletes it there. It returns ErrTagRejected when origin already has
+	// a different tag of the same name.
+	Push(name string, deletion bool) error
+	// ForcePush publishes a tag to origin, replacing a different tag of the same name. It is used for alias
+	// tags, which move from release to release.
+	ForcePush(name string) error
+	// Fetch refreshes local tags from origin. Local tags that differ from origin are replaced, as alias tags
+	// move from release to release.
+	Fetch() error
+}
+
+// gitStore keeps tags in a go-git repository on disk and uses the git CLI to talk to origin.
+type gitStore struct {
+	repo *git.Repository
+	// dir is where the git CLI is run. It is empty for repositories not on disk.
+	dir string
+}
+
+func (s *gitStore) Tags() ([]*plumbing.Reference, error) {
+	iter, err := s.repo.Tags()
+	if err != nil {
+		return nil, err
+	}
+	return collectRefs(iter)
+}
+
+func (s *gitStore) CreateTag(name string, hash plumbing.Hash) error {
+	_, err := s.repo.CreateTag(name, hash, nil)
+	return err
+}
+
+func (s *gitStore) DeleteTag(name string) error {
+	return s.repo.DeleteTag(name)
+}
+
+func (s *gitStore) Push(name string, deletion bool) error {
+	args := []string{"push", "origin", "refs/tags/" + name}
+	if deletion {
+		args = []string{"push", "origin", "--delete", "refs/tags/" + name}
+	}
+	cmd, err := s.gitCommand(args...)
+	if err != nil {
+		return err
+	}
+
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		return nil
+	}
+	if !deletion && (strings.Contains(string(out), "already exists") || strings.Contains(string(out), "[rejected]")) {
+		return ErrTagRejected
+	}
+	return &PushError{Tag: name, Output: strings.TrimSpace(string(out))}
+}
+
+func (s *gitStore) ForcePush(name string) error {
+	cmd, err := s.gitCommand("push", "--force", "origin", "refs/tags/"+name)
+	if err != nil {
+		return err
+	}
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return &PushError{Tag: name, Output: strings.TrimSpace(string(out))}
+	}
+	return nil
+}
+
+func (s *gitStore) Fetch() error {
+	cmd, err := s.gitCommand("fetch", "origin", "+refs/tags/*:refs/tags/*")
+	if err != nil {
+		return err
+	}
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return &PushError{Output: strings.TrimSpace(string(out))}
+	}
+	return nil
+}
+
+// gitCommand prepares a git CLI invocation against the repository, for operations go-git does not cover.
+func (s *gitStore) gitCommand(args ...string) (*exec.Cmd, error) {
+	if s.dir == "" {
+		return nil, fmt.Errorf("repository is not on disk, push is not supported")
+	}
+	gitPath, err := exec.Command("which", "git").Output()
+	if err != nil {
+		return nil, fmt.Errorf("could not find git on host system, push is not supported")
+	}
+
+	gitCmd := strings.Replace(string(gitPath), "\n", "", -1)
+	return exec.Command(gitCmd, append([]string{"-C", s.dir}, args...)...), nil
+}
+
+// MemoryStore is a TagStore backed by an in-memory go-git repository, with origin simulated by a second
+// in-memory storage. It is intended for tests and dry experiments.
+type MemoryStore struct {
+	repo *git.Repository
+	// Origin holds the tags that have been pushed.
+	Origin *memory.Storage
+}
+
+// NewMemoryRepo creates an empty in-memory repository with a worktree, backed by a MemoryStore.
+func NewMemoryRepo() (*Repo, *MemoryStore, error) {
+	r, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s := &MemoryStore{repo: r, Origin: memory.NewStorage()}
+	return NewRepoWithStore(r, s), s, nil
+}
+
+func (s *MemoryStore) Tags() ([]*plumbing.Reference, error) {
+	iter, err := s.repo.Tags()
+	if err != nil {
+		return nil, err
+	}
+	return collectRefs(iter)
+}
+
+func (s *MemoryStore) CreateTag(name string, hash plumbing.Hash) error {
+	_, err := s.repo.CreateTag(name, hash, nil)
+	return err
+}
+
+func (s *MemoryStore) DeleteTag(name string) error {
+	return s.repo.DeleteTag(name)
+}
+
+func (s *MemoryStore) Push(name string, deletion bool) error {
+	refName := plumbing.NewTagReferenceName(name)
+	if deletion {
+		return s.Origin.RemoveReference(refName)
+	}
+
+	local, err := s.repo.Reference(refName, false)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrTagNotFound, name)
+	}
+	remote, err := s.Origin.Reference(refName)
+	if err == nil && remote.Hash() != local.Hash() {
+		return ErrTagRejected
+	}
+	if err != nil && !errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return err
+	}
+	return s.Origin.SetReference(local)
+}
+
+func (s *MemoryStore) ForcePush(name string) error {
+	local, err := s.repo.Reference(plumbing.NewTagReferenceName(name), false)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrTagNotFound, name)
+	}
+	return s.Origin.SetReference(local)
+}
+
+// Fetch copies the tags of Origin, replacing local tags of the same name like a forced git fetch.
+func (s *MemoryStore) Fetch() error {
+	iter, err := s.Origin.IterReferences()
+	if err != nil {
+		return err
+	}
+	refs, err := collectRefs(storer.NewReferenceFilteredIter(func(ref *plumbing.Reference) bool {
+		return ref.Name().IsTag()
+	}, iter))
+	if err != nil {
+		return err
+	}
+
+	for _, ref := range refs {
+		if err := s.repo.Storer.SetReference(ref); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func collectRefs(iter storer.ReferenceIter) ([]*plumbing.Reference, error) {
+	refs := make([]*plumbing.Reference, 0)
+	err := iter.ForEach(func(ref *plumbing.Reference) error {
+		refs = append(refs, ref)
+		return nil
+	})
+	return refs, err
+}
@@ -0,0 +1,67 @@
+package ver
+
+import (
+	"io"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	pretty "github.com/andanhm/go-prettytime"
+)
+
+// PendingRelease is the template data for a version that has not been tagged yet.
+type PendingRelease struct {
+	Version string
+	Hash    string
+	When    time.Time
+}
+
+// TemplateFuncs are the functions available to output templates, in addition to the text/template builtins.
+var TemplateFuncs = template.FuncMap{
+	// short abbreviates a commit hash.
+	"short": func(h string) string {
+		if len(h) < 7 {
+			return h
+		}
+		return h[:7]
+	},
+	// date formats a time with a Go layout, eg. {{ date "2006-01-02" .When }}.
+	"date": func(layout string, t time.Time) string {
+		return t.Format(layout)
+	},
+	// ago renders a time relative to now, eg. "3 days ago".
+	"ago": func(t time.Time) string {
+		return pretty.Format(t)
+	},
+	// subject returns the first line of a commit message.
+	"subject": func(msg string) string {
+		return strings.SplitN(strings.TrimSpace(msg), "\n", 2)[0]
+	},
+	"join":  strings.Join,
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+}
+
+// ParseTemplate parses an output template. Templates are rendered once per tag group (or release), and a
+// trailing newline is added when the template does not end with one.
+func ParseTemplate(text string) (*template.Template, error) {
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	return template.New("output").Funcs(TemplateFuncs).Parse(text)
+}
+
+// ParseTemplateFile parses an output template read from a file.
+func ParseTemplateFile(path string) (*template.Template, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseTemplate(string(b))
+}
+
+// Render executes tmpl with the tag group as data.
+func (cvt *CalVerTagGroup) Render(w io.Writer, tmpl *template.Template) error {
+	return tmpl.Execute(w, cvt)
+}
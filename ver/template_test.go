@@ -0,0 +1,36 @@
+package ver
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	when := time.Date(2024, 3, 15, 9, 30, 0, 0, time.UTC)
+	group := &CalVerTagGroup{
+		Hash:   "abc1234",
+		Tags:   []string{"2024.03.15"},
+		When:   when,
+		Commit: &object.Commit{Author: object.Signature{Name: "dev", When: when}},
+		ChangeLog: []*object.Commit{
+			testObject("feat(cli): templates\n\nlong body", 0),
+			testObject("tidy up", 0),
+		},
+	}
+
+	tmpl, err := ParseTemplate(`{{.Tag}} {{.Hash}} {{date "2006-01-02" .When}} {{upper .Commit.Author.Name}}` +
+		`{{range .ChangeLog}}|{{subject .Message}}{{end}}{{range .Groups}}|{{.Title}}={{len .Commits}}{{end}}`)
+	require.NoError(t, err)
+
+	var b bytes.Buffer
+	require.NoError(t, group.Render(&b, tmpl))
+	assert.Equal(t, "2024.03.15 abc1234 2024-03-15 DEV|feat(cli): templates|tidy up|Features=1|Other=1\n", b.String())
+
+	_, err = ParseTemplate("{{.Missing")
+	assert.Error(t, err)
+}
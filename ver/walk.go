@@ -0,0 +1,141 @@
+package ver
+
+import (
+	"container/heap"
+	"fmt"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// queuedCommit is a commit waiting in a commitQueue with its generation number.
+type queuedCommit struct {
+	*object.Commit
+	generation int
+}
+
+// commitQueue is a max-heap of commits by generation number, so a commit is only popped after every queued
+// commit it is reachable from.
+type commitQueue []queuedCommit
+
+func (q commitQueue) Len() int           { return len(q) }
+func (q commitQueue) Less(i, j int) bool { return q[i].generation > q[j].generation }
+func (q commitQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *commitQueue) Push(x any)        { *q = append(*q, x.(queuedCommit)) }
+func (q *commitQueue) Pop() any {
+	old := *q
+	c := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return c
+}
+
+// excludedOnly reports whether every queued commit is in excluded.
+func (q commitQueue) excludedOnly(excluded map[plumbing.Hash]bool) bool {
+	for _, c := range q {
+		if !excluded[c.Hash] {
+			return false
+		}
+	}
+	return true
+}
+
+// generation returns the generation number of c: 1 for a root commit, otherwise one more than the highest of
+// its parents. A commit's ancestors all have lower numbers, whatever their dates. Numbers are cached on the
+// Repo, so the history below a commit is only walked once.
+func (r *Repo) generation(c *object.Commit) (int, error) {
+	if g, ok := r.generations[c.Hash]; ok {
+		return g, nil
+	}
+	if r.generations == nil {
+		r.generations = make(map[plumbing.Hash]int)
+	}
+
+	stack := []*object.Commit{c}
+	for len(stack) > 0 {
+		top := stack[len(stack)-1]
+		if _, ok := r.generations[top.Hash]; ok {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+
+		g, pending := 1, false
+		for _, p := range top.ParentHashes {
+			pg, ok := r.generations[p]
+			if !ok {
+				pc, err := r.git.CommitObject(p)
+				if err != nil {
+					return 0, fmt.Errorf("could not walk history of %s: %w", p, err)
+				}
+				stack = append(stack, pc)
+				pending = true
+				continue
+			}
+			g = max(g, pg+1)
+		}
+		if !pending {
+			r.generations[top.Hash] = g
+			stack = stack[:len(stack)-1]
+		}
+	}
+	return r.generations[c.Hash], nil
+}
+
+// between returns the commits reachable from to but not from from, like git rev-list from..to, in no
+// particular order. Both histories are walked together by generation number, and the walk stops once only
+// commits reachable from from are queued, so its cost is bounded by the commits since the merge base rather
+// than the whole history. Unlike a walk by date, the result does not depend on committer dates. A nil from
+// returns the full history of to.
+func (r *Repo) between(from, to *object.Commit) ([]*object.Commit, error) {
+	if from != nil && from.Hash == to.Hash {
+		return make([]*object.Commit, 0), nil
+	}
+
+	seen := make(map[plumbing.Hash]bool)
+	excluded := make(map[plumbing.Hash]bool)
+	queue := &commitQueue{}
+	push := func(c *object.Commit) error {
+		g, err := r.generation(c)
+		if err != nil {
+			return err
+		}
+		seen[c.Hash] = true
+		heap.Push(queue, queuedCommit{Commit: c, generation: g})
+		return nil
+	}
+
+	if err := push(to); err != nil {
+		return nil, err
+	}
+	if from != nil {
+		excluded[from.Hash] = true
+		if err := push(from); err != nil {
+			return nil, err
+		}
+	}
+
+	logs := make([]*object.Commit, 0)
+	for queue.Len() > 0 && !queue.excludedOnly(excluded) {
+		c := heap.Pop(queue).(queuedCommit)
+		// Every commit c is reachable from has been popped already, so excluded is final for c.
+		if !excluded[c.Hash] {
+			logs = append(logs, c.Commit)
+		}
+
+		for _, p := range c.ParentHashes {
+			if excluded[c.Hash] {
+				excluded[p] = true
+			}
+			if seen[p] {
+				continue
+			}
+			pc, err := r.git.CommitObject(p)
+			if err != nil {
+				return nil, fmt.Errorf("could not walk history of %s: %w", p, err)
+			}
+			if err := push(pc); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return logs, nil
+}